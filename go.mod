@@ -4,7 +4,9 @@ go 1.21
 
 require (
 	github.com/palantir/conjure-go-runtime/v2 v2.79.0
+	github.com/palantir/pkg/metrics v1.7.0
 	github.com/palantir/pkg/retry v1.2.0
+	github.com/palantir/pkg/tlsconfig v1.3.0
 	github.com/palantir/witchcraft-go-error v1.39.0
 	github.com/palantir/witchcraft-go-logging v1.57.0
 	github.com/palantir/witchcraft-go-params v1.36.0
@@ -18,11 +20,9 @@ require (
 	github.com/palantir/go-metrics v1.1.1 // indirect
 	github.com/palantir/pkg v1.1.0 // indirect
 	github.com/palantir/pkg/bytesbuffers v1.2.0 // indirect
-	github.com/palantir/pkg/metrics v1.7.0 // indirect
 	github.com/palantir/pkg/refreshable v1.5.0 // indirect
 	github.com/palantir/pkg/refreshable/v2 v2.0.0 // indirect
 	github.com/palantir/pkg/safejson v1.1.0 // indirect
-	github.com/palantir/pkg/tlsconfig v1.3.0 // indirect
 	github.com/palantir/pkg/uuid v1.2.0 // indirect
 	github.com/palantir/witchcraft-go-tracing v1.38.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
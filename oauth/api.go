@@ -21,4 +21,25 @@ import (
 // ClientCredentialClient returns a client_credentials token
 type ClientCredentialClient interface {
 	CreateClientCredentialToken(ctx context.Context, clientID, clientSecret string) (string, error)
+	// CreateClientCredentialTokenResponse is like CreateClientCredentialToken, but returns the full token response,
+	// including the granted scope and the lifetime of the token.
+	CreateClientCredentialTokenResponse(ctx context.Context, clientID, clientSecret string) (*TokenResponse, error)
+	// RefreshToken exchanges refreshToken for a new access token using the refresh_token grant. The client
+	// authenticates in the same way as for CreateClientCredentialToken, as RFC 6749 Section 6 requires of confidential
+	// clients. Public clients, which have no secret, should be configured with
+	// WithClientAuthMethod(ClientAuthMethodNone) so that only the client ID is sent.
+	RefreshToken(ctx context.Context, clientID, clientSecret, refreshToken string) (string, error)
+	// RefreshTokenResponse is like RefreshToken, but returns the full token response. Callers that persist refresh
+	// tokens should store its RefreshToken if it is non-empty, since servers that rotate refresh tokens revoke the
+	// one that was used.
+	RefreshTokenResponse(ctx context.Context, clientID, clientSecret, refreshToken string) (*TokenResponse, error)
+}
+
+// JWTBearerClient returns a token for a JWT assertion. The clients returned by NewClientCredentialClient and
+// NewClientCredentialClientWithEndpoint implement it, so callers can obtain one using a type assertion, such as
+// client.(oauth.JWTBearerClient).
+type JWTBearerClient interface {
+	// CreateJWTBearerToken exchanges a signed JWT assertion for an access token using the JWT bearer grant defined by
+	// RFC 7523 Section 2.1. No client authentication is sent, since the assertion identifies the client.
+	CreateJWTBearerToken(ctx context.Context, assertion string) (string, error)
 }
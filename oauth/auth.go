@@ -0,0 +1,131 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/url"
+
+	werror "github.com/palantir/witchcraft-go-error"
+	wparams "github.com/palantir/witchcraft-go-params"
+)
+
+const clientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ClientAuthMethod is the method a client uses to authenticate to the token endpoint. The values match those of the
+// "token_endpoint_auth_method" client metadata defined by RFC 7591 and OpenID Connect.
+type ClientAuthMethod string
+
+const (
+	// ClientAuthMethodSecretPost sends the client ID and secret as the client_id and client_secret form parameters.
+	// This is the default.
+	ClientAuthMethodSecretPost ClientAuthMethod = "client_secret_post"
+	// ClientAuthMethodSecretBasic sends the client ID and secret using HTTP Basic authentication.
+	ClientAuthMethodSecretBasic ClientAuthMethod = "client_secret_basic"
+	// ClientAuthMethodPrivateKeyJWT sends the client ID and a signed JWT assertion obtained from the
+	// ClientAssertionProvider configured using WithClientAssertionProvider. The client secret is not sent.
+	ClientAuthMethodPrivateKeyJWT ClientAuthMethod = "private_key_jwt"
+	// ClientAuthMethodTLSClientAuth sends only the client ID. The client authenticates using a CA-issued certificate
+	// presented by the TLS transport, as defined by RFC 8705 Section 2.1. See WithTLSClientAuth.
+	ClientAuthMethodTLSClientAuth ClientAuthMethod = "tls_client_auth"
+	// ClientAuthMethodSelfSignedTLSClientAuth sends only the client ID. The client authenticates using a self-signed
+	// certificate presented by the TLS transport, as defined by RFC 8705 Section 2.2. See WithSelfSignedTLSClientAuth.
+	ClientAuthMethodSelfSignedTLSClientAuth ClientAuthMethod = "self_signed_tls_client_auth"
+	// ClientAuthMethodNone sends only the client ID. This is used by public clients that have no secret.
+	ClientAuthMethodNone ClientAuthMethod = "none"
+)
+
+// ClientAssertionProvider returns a signed JWT that authenticates the client with the provided ID, as defined by
+// RFC 7523 Section 2.2.
+type ClientAssertionProvider func(ctx context.Context, clientID string) (string, error)
+
+// ClientAuthParam configures the ClientAuthMethod set by WithClientAuthMethod.
+type ClientAuthParam func(*clientAuth)
+
+// WithClientAssertionProvider sets the provider of the client assertions sent by ClientAuthMethodPrivateKeyJWT.
+func WithClientAssertionProvider(provider ClientAssertionProvider) ClientAuthParam {
+	return func(a *clientAuth) {
+		a.assertionProvider = provider
+	}
+}
+
+// WithClientAuthMethod configures how the client authenticates to the token endpoint. Requests made with an unknown
+// method, or with ClientAuthMethodPrivateKeyJWT and no ClientAssertionProvider, fail without contacting the server.
+func WithClientAuthMethod(method ClientAuthMethod, params ...ClientAuthParam) ClientOption {
+	return func(s *serviceClient) {
+		s.clientAuth = clientAuth{
+			method: method,
+		}
+		for _, param := range params {
+			param(&s.clientAuth)
+		}
+	}
+}
+
+// WithClientAssertion configures client credentials requests to authenticate the client using ClientAuthMethodPrivateKeyJWT
+// with assertions obtained from provider. It is shorthand for
+// WithClientAuthMethod(ClientAuthMethodPrivateKeyJWT, WithClientAssertionProvider(provider)).
+func WithClientAssertion(provider ClientAssertionProvider) ClientOption {
+	return WithClientAuthMethod(ClientAuthMethodPrivateKeyJWT, WithClientAssertionProvider(provider))
+}
+
+type clientAuth struct {
+	method            ClientAuthMethod
+	assertionProvider ClientAssertionProvider
+}
+
+// basicAuth is the HTTP Basic authentication sent with a request.
+type basicAuth struct {
+	username string
+	password string
+	// secret is the client secret before it was form-encoded into password, which is redacted from errors in case the
+	// server echoes it.
+	secret string
+}
+
+// apply adds the client credentials to the form values of a token request and returns the HTTP Basic authentication
+// to send with it, if any.
+func (a clientAuth) apply(ctx context.Context, values url.Values, clientID, clientSecret string) (*basicAuth, error) {
+	switch a.method {
+	case "", ClientAuthMethodSecretPost:
+		values.Set("client_id", clientID)
+		values.Set("client_secret", clientSecret)
+	case ClientAuthMethodSecretBasic:
+		// RFC 6749 Section 2.3.1 requires the credentials to be form-encoded before they are used for Basic auth
+		return &basicAuth{
+			username: url.QueryEscape(clientID),
+			password: url.QueryEscape(clientSecret),
+			secret:   clientSecret,
+		}, nil
+	case ClientAuthMethodPrivateKeyJWT:
+		if a.assertionProvider == nil {
+			return nil, werror.ErrorWithContextParams(ctx, "client authentication method requires a client assertion provider",
+				werror.SafeParam("clientAuthMethod", a.method))
+		}
+		assertion, err := a.assertionProvider(ctx, clientID)
+		if err != nil {
+			return nil, werror.WrapWithContextParams(ctx, err, "failed to create client assertion")
+		}
+		values.Set("client_id", clientID)
+		values.Set("client_assertion_type", clientAssertionTypeJWTBearer)
+		values.Set("client_assertion", assertion)
+	case ClientAuthMethodTLSClientAuth, ClientAuthMethodSelfSignedTLSClientAuth, ClientAuthMethodNone:
+		values.Set("client_id", clientID)
+	default:
+		return nil, werror.ErrorWithContextParams(wparams.ContextWithSafeParam(ctx, "clientAuthMethod", a.method),
+			"unsupported client authentication method")
+	}
+	return nil, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithClientAuthMethod(t *testing.T) {
+	ctx := context.Background()
+	var (
+		gotBody      url.Values
+		gotBasicAuth []string
+	)
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotBody = url.Values{}
+		assert.NoError(t, codecs.FormURLEncoded.Decode(req.Body, &gotBody))
+		gotBasicAuth = nil
+		if user, password, ok := req.BasicAuth(); ok {
+			gotBasicAuth = []string{user, password}
+		}
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name          string
+		opts          []ClientOption
+		wantBody      url.Values
+		wantBasicAuth []string
+	}{
+		{
+			name: "default",
+			wantBody: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {"client:id"},
+				"client_secret": {"secret"},
+			},
+		},
+		{
+			name: "client_secret_basic",
+			opts: []ClientOption{WithClientAuthMethod(ClientAuthMethodSecretBasic)},
+			wantBody: url.Values{
+				"grant_type": {"client_credentials"},
+			},
+			wantBasicAuth: []string{"client%3Aid", "secret"},
+		},
+		{
+			name: "private_key_jwt",
+			opts: []ClientOption{WithClientAuthMethod(ClientAuthMethodPrivateKeyJWT,
+				WithClientAssertionProvider(func(ctx context.Context, clientID string) (string, error) {
+					return "assertion-for-" + clientID, nil
+				}))},
+			wantBody: url.Values{
+				"grant_type":            {"client_credentials"},
+				"client_id":             {"client:id"},
+				"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+				"client_assertion":      {"assertion-for-client:id"},
+			},
+		},
+		{
+			name: "WithClientAssertion",
+			opts: []ClientOption{WithClientAssertion(func(ctx context.Context, clientID string) (string, error) {
+				return "assertion", nil
+			})},
+			wantBody: url.Values{
+				"grant_type":            {"client_credentials"},
+				"client_id":             {"client:id"},
+				"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+				"client_assertion":      {"assertion"},
+			},
+		},
+		{
+			name: "none",
+			opts: []ClientOption{WithClientAuthMethod(ClientAuthMethodNone)},
+			wantBody: url.Values{
+				"grant_type": {"client_credentials"},
+				"client_id":  {"client:id"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := NewClientCredentialClient(tokenHTTPClient, tc.opts...).CreateClientCredentialToken(ctx, "client:id", "secret")
+			require.NoError(t, err)
+			assert.Equal(t, "token", token)
+			assert.Equal(t, tc.wantBody, gotBody)
+			assert.Equal(t, tc.wantBasicAuth, gotBasicAuth)
+		})
+	}
+
+	t.Run("private_key_jwt without assertion provider", func(t *testing.T) {
+		_, err := NewClientCredentialClient(tokenHTTPClient, WithClientAuthMethod(ClientAuthMethodPrivateKeyJWT)).
+			CreateClientCredentialToken(ctx, "client", "secret")
+		require.EqualError(t, err, "client authentication method requires a client assertion provider")
+	})
+	t.Run("unsupported method", func(t *testing.T) {
+		_, err := NewClientCredentialClient(tokenHTTPClient, WithClientAuthMethod("unknown")).
+			CreateClientCredentialToken(ctx, "client", "secret")
+		require.EqualError(t, err, "unsupported client authentication method")
+	})
+}
@@ -15,11 +15,15 @@
 package oauth
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
 	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
@@ -30,101 +34,433 @@ import (
 const (
 	clientCredentialsEndpoint  = "/oauth2/token"
 	clientCredentialsGrantType = "client_credentials"
+	refreshTokenGrantType      = "refresh_token"
+	jwtBearerGrantType         = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	// maxErrorBodyLength is the maximum number of bytes read from an error response. Longer bodies are truncated, which
+	// bounds the memory used for, and the size of the parameters attached to, errors from misbehaving servers.
+	maxErrorBodyLength = 8 << 10
+	// maxErrorBodySnippetLength is the maximum number of bytes of a non-JSON error response included in errors.
+	maxErrorBodySnippetLength = 1024
 )
 
 type serviceClient struct {
 	client                   httpclient.Client
 	clientCredentialEndpoint string
+	responseDecoder          codecs.Decoder
+	clientAuth               clientAuth
+	// successStatusCodes, if non-empty, is the set of status codes accepted for a token response.
+	successStatusCodes map[int]struct{}
+	// contentType, if non-empty, overrides the Content-Type header of token requests.
+	contentType string
+	// scopes, if non-empty, are requested by client credentials token requests.
+	scopes []string
+	// audience, if non-empty, is requested by client credentials token requests.
+	audience string
+	// resources, if non-empty, are requested by client credentials token requests.
+	resources []string
+	// formParams are added to the body of token requests.
+	formParams url.Values
+	// jsonRequestBody sends request parameters as a JSON object instead of a form-encoded body.
+	jsonRequestBody bool
+	transport       transportConfig
+	// metrics enables recording the duration and outcome of requests.
+	metrics bool
+	// allowAnyTokenType disables the check that token responses contain a bearer token.
+	allowAnyTokenType bool
+	// debug enables debug logging of requests and responses.
+	debug bool
+	// httpClient, if non-nil, is used to send requests to baseURL instead of client.
+	httpClient *http.Client
+	baseURL    string
+	// rawQuery is the query of the token URL, which is sent with requests made using httpClient.
+	rawQuery string
 }
 
-type oauth2Response struct {
+// ClientOption configures optional behavior of a client returned by this package.
+type ClientOption func(*serviceClient)
+
+// TokenResponse is a successful response from a token endpoint, as defined in RFC 6749 Section 5.1.
+// https://datatracker.ietf.org/doc/html/rfc6749#section-5.1
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	// TokenType is normalized so that a bearer token type is always "Bearer", regardless of its case in the response.
+	TokenType string `json:"token_type"`
+	// ExpiresIn is the lifetime of the access token in seconds, or 0 if the server did not provide it.
+	ExpiresIn int `json:"expires_in"`
+	// RefreshToken is empty if the server did not issue one. A refresh token returned in response to a refresh_token
+	// grant replaces the one that was used, which the server may have revoked.
 	RefreshToken string `json:"refresh_token"`
 	Scope        string `json:"scope"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int    `json:"expires_in"`
-	AccessToken  string `json:"access_token"`
+	// IDToken is the OpenID Connect ID token, which is only returned if the "openid" scope was requested. Its claims
+	// can be decoded using ParseIDTokenClaims.
+	IDToken string `json:"id_token"`
 }
 
 // NewClientCredentialClient returns an oauth2.Client configured using the provided client.
-// The client will use the httpclient's configured BaseURIs.
-func NewClientCredentialClient(client httpclient.Client) ClientCredentialClient {
-	return &serviceClient{
-		client:                   client,
-		clientCredentialEndpoint: clientCredentialsEndpoint,
-	}
+// The client will use the httpclient's configured BaseURIs. If several are configured, a request that fails with a
+// connection error or a 503 response is retried against the next base URI, so a token request succeeds as long as one
+// token endpoint is reachable within the client's retry limit.
+func NewClientCredentialClient(client httpclient.Client, opts ...ClientOption) ClientCredentialClient {
+	return NewClientCredentialClientWithEndpoint(client, clientCredentialsEndpoint, opts...)
 }
 
 // NewClientCredentialClientWithEndpoint returns an oauth2.Client configured using the provided client and oauth endpoint.
 // The client will use the httpclient's configured BaseURIs.
-func NewClientCredentialClientWithEndpoint(client httpclient.Client, endpoint string) ClientCredentialClient {
-	return &serviceClient{
+func NewClientCredentialClientWithEndpoint(client httpclient.Client, endpoint string, opts ...ClientOption) ClientCredentialClient {
+	return newServiceClient(client, endpoint, opts)
+}
+
+func newServiceClient(client httpclient.Client, endpoint string, opts []ClientOption) *serviceClient {
+	s := &serviceClient{
 		client:                   client,
 		clientCredentialEndpoint: endpoint,
+		responseDecoder:          codecs.JSON,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *serviceClient) CreateClientCredentialToken(ctx context.Context, clientID, clientSecret string) (string, error) {
+	resp, err := s.CreateClientCredentialTokenResponse(ctx, clientID, clientSecret)
+	if err != nil {
+		return "", err
+	}
+	return resp.AccessToken, nil
+}
+
+func (s *serviceClient) CreateClientCredentialTokenResponse(ctx context.Context, clientID, clientSecret string) (*TokenResponse, error) {
 	urlValues := url.Values{
-		"grant_type":    []string{clientCredentialsGrantType},
-		"client_id":     []string{clientID},
-		"client_secret": []string{clientSecret},
-	}
-	var oauth2Resp oauth2Response
-	_, err := s.client.Do(ctx,
-		httpclient.WithRPCMethodName("CreateClientCredentialToken"),
-		httpclient.WithRequestMethod(http.MethodPost),
-		httpclient.WithPath(s.clientCredentialEndpoint),
-		httpclient.WithRequestBody(urlValues, codecs.FormURLEncoded),
-		httpclient.WithJSONResponse(&oauth2Resp),
-		httpclient.WithRequestErrorDecoder(errorDecoder{ctx}),
-	)
+		"grant_type": []string{clientCredentialsGrantType},
+	}
+	if len(s.scopes) > 0 {
+		urlValues.Set("scope", strings.Join(s.scopes, " "))
+	}
+	if s.audience != "" {
+		urlValues.Set("audience", s.audience)
+	}
+	if len(s.resources) > 0 {
+		urlValues["resource"] = append([]string(nil), s.resources...)
+	}
+	auth, err := s.clientAuth.apply(ctx, urlValues, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.requestToken(ctx, "CreateClientCredentialToken", urlValues, auth)
+	if err != nil {
+		return nil, werror.WrapWithContextParams(ctx, err, "failed to make create client credential token request")
+	}
+	return resp, nil
+}
+
+func (s *serviceClient) RefreshToken(ctx context.Context, clientID, clientSecret, refreshToken string) (string, error) {
+	resp, err := s.RefreshTokenResponse(ctx, clientID, clientSecret, refreshToken)
 	if err != nil {
-		return "", werror.WrapWithContextParams(ctx, err, "failed to make create client credential token request")
+		return "", err
+	}
+	return resp.AccessToken, nil
+}
+
+func (s *serviceClient) RefreshTokenResponse(ctx context.Context, clientID, clientSecret, refreshToken string) (*TokenResponse, error) {
+	urlValues := url.Values{
+		"grant_type":    []string{refreshTokenGrantType},
+		"refresh_token": []string{refreshToken},
+	}
+	auth, err := s.clientAuth.apply(ctx, urlValues, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.requestToken(ctx, "RefreshToken", urlValues, auth)
+	if err != nil {
+		return nil, werror.WrapWithContextParams(ctx, err, "failed to make refresh token request")
+	}
+	return resp, nil
+}
+
+var _ JWTBearerClient = (*serviceClient)(nil)
+
+func (s *serviceClient) CreateJWTBearerToken(ctx context.Context, assertion string) (string, error) {
+	urlValues := url.Values{
+		"grant_type": []string{jwtBearerGrantType},
+		"assertion":  []string{assertion},
+	}
+	resp, err := s.requestToken(ctx, "CreateJWTBearerToken", urlValues, nil)
+	if err != nil {
+		return "", werror.WrapWithContextParams(ctx, err, "failed to make create JWT bearer token request")
+	}
+	return resp.AccessToken, nil
+}
+
+// requestToken posts urlValues to the token endpoint and decodes the token response.
+func (s *serviceClient) requestToken(ctx context.Context, rpcName string, urlValues url.Values, auth *basicAuth) (*TokenResponse, error) {
+	if err := s.addFormParams(ctx, urlValues); err != nil {
+		return nil, err
+	}
+	var resp TokenResponse
+	if err := s.postForm(ctx, rpcName, s.clientCredentialEndpoint, urlValues, &resp, s.responseDecoder, auth); err != nil {
+		return nil, err
+	}
+	resp.TokenType = normalizeTokenType(resp.TokenType)
+	if resp.AccessToken == "" {
+		return nil, werror.ErrorWithContextParams(ctx, "token endpoint returned a response without an access token",
+			werror.SafeParam("endpoint", s.clientCredentialEndpoint),
+			werror.SafeParam("tokenType", resp.TokenType),
+			werror.SafeParam("scope", resp.Scope))
+	}
+	if !s.allowAnyTokenType && resp.TokenType != "" && resp.TokenType != tokenTypeBearer {
+		return nil, werror.ErrorWithContextParams(ctx, "token endpoint returned a token that is not a bearer token",
+			werror.SafeParam("tokenType", resp.TokenType))
+	}
+	return &resp, nil
+}
+
+// postForm posts the form-encoded urlValues to path, authenticated using auth if it is non-nil, and decodes the
+// response into out using decoder. Secrets sent with the request are redacted from the returned error.
+func (s *serviceClient) postForm(ctx context.Context, rpcName, path string, urlValues url.Values, out interface{}, decoder codecs.Decoder, auth *basicAuth) error {
+	secrets := sensitiveValues(urlValues)
+	if auth != nil {
+		secrets = append(secrets, auth.password, auth.secret)
+	}
+	errorDecoder := s.errorDecoder(ctx, secrets)
+	s.logRequest(ctx, rpcName, path, urlValues)
+	start := time.Now()
+	var resp *http.Response
+	var err error
+	if s.httpClient != nil {
+		resp, err = s.doHTTP(ctx, path, urlValues, out, decoder, auth, errorDecoder)
+	} else {
+		body, encoder := s.requestBody(urlValues)
+		params := []httpclient.RequestParam{
+			httpclient.WithRPCMethodName(rpcName),
+			httpclient.WithRequestMethod(http.MethodPost),
+			httpclient.WithPath(path),
+			httpclient.WithRequestBody(body, encoder),
+			httpclient.WithResponseBody(out, decoder),
+			httpclient.WithRequestErrorDecoder(errorDecoder),
+		}
+		if s.contentType != "" {
+			params = append(params, httpclient.WithHeader("Content-Type", s.contentType))
+		}
+		if auth != nil {
+			params = append(params, httpclient.WithRequestBasicAuth(auth.username, auth.password))
+		}
+		resp, err = s.client.Do(ctx, params...)
+	}
+	s.recordRequest(ctx, rpcName, start, err)
+	s.logResponse(ctx, rpcName, path, resp, err)
+	return err
+}
+
+// ValidateClientCredentials performs a single client_credentials token request using the provided client and discards
+// the returned token. It returns nil if the credentials were accepted and the error returned by the client otherwise.
+// This is intended for startup and health checks that verify credentials without starting a token refresher.
+func ValidateClientCredentials(ctx context.Context, client ClientCredentialClient, clientID, clientSecret string) error {
+	if _, err := client.CreateClientCredentialToken(ctx, clientID, clientSecret); err != nil {
+		return werror.WrapWithContextParams(ctx, err, "failed to validate client credentials")
+	}
+	return nil
+}
+
+// WithContentType overrides the Content-Type header sent with token requests, which defaults to
+// "application/x-www-form-urlencoded". It does not change how the request body is encoded, so the provided value
+// should describe form-encoded content, such as "application/x-www-form-urlencoded; charset=UTF-8", or JSON content
+// if WithJSONRequestBody is used.
+func WithContentType(contentType string) ClientOption {
+	return func(s *serviceClient) {
+		s.contentType = contentType
+	}
+}
+
+// WithScopes configures client credentials token requests to request the provided scopes, which are sent as the
+// space-delimited "scope" parameter. By default, no scope is requested and the server grants its default scope.
+func WithScopes(scopes ...string) ClientOption {
+	return func(s *serviceClient) {
+		s.scopes = scopes
+	}
+}
+
+// WithAudience configures client credentials token requests to send the "audience" parameter, which identifies the API
+// the token is intended for. Providers such as Auth0 require it to issue a JWT access token that the API can validate.
+func WithAudience(audience string) ClientOption {
+	return func(s *serviceClient) {
+		s.audience = audience
+	}
+}
+
+// WithResources configures client credentials token requests to send a "resource" parameter for each of the provided
+// URIs, which identify the APIs the token is intended for as defined by RFC 8707. Each resource is sent as a separate
+// parameter, as the RFC requires.
+// https://datatracker.ietf.org/doc/html/rfc8707#section-2
+func WithResources(resources ...string) ClientOption {
+	return func(s *serviceClient) {
+		s.resources = resources
+	}
+}
+
+// WithSuccessStatusCodes configures the set of HTTP status codes for which a token response is decoded. Responses
+// with any other status code are treated as errors. By default, every status code below 400 is accepted.
+func WithSuccessStatusCodes(statusCodes ...int) ClientOption {
+	return func(s *serviceClient) {
+		s.successStatusCodes = make(map[int]struct{}, len(statusCodes))
+		for _, statusCode := range statusCodes {
+			s.successStatusCodes[statusCode] = struct{}{}
+		}
+	}
+}
+
+// errorDecoder returns the error decoder for a request that sent the provided secrets, which are redacted from the
+// parameters of the returned errors in case the server echoes them.
+func (s *serviceClient) errorDecoder(ctx context.Context, secrets []string) errorDecoder {
+	return errorDecoder{
+		ctx:                ctx,
+		successStatusCodes: s.successStatusCodes,
+		secrets:            secrets,
 	}
-	return oauth2Resp.AccessToken, nil
 }
 
 type errorDecoder struct {
-	ctx context.Context
+	ctx                context.Context
+	successStatusCodes map[int]struct{}
+	secrets            []string
 }
 
-func (errorDecoder) Handles(resp *http.Response) bool {
-	return resp != nil && resp.Body != nil && resp.StatusCode > 399
+func (d errorDecoder) Handles(resp *http.Response) bool {
+	if resp == nil || resp.Body == nil {
+		return false
+	}
+	if len(d.successStatusCodes) > 0 {
+		_, ok := d.successStatusCodes[resp.StatusCode]
+		return !ok
+	}
+	return resp.StatusCode > 399
 }
 
 func (d errorDecoder) DecodeError(resp *http.Response) error {
 	ctx := wparams.ContextWithSafeParam(d.ctx, "statusCode", resp.StatusCode)
-	body, err := io.ReadAll(resp.Body)
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		ctx = wparams.ContextWithSafeParam(ctx, retryAfterParam, retryAfter)
+	}
+	if resp.StatusCode < 400 {
+		return werror.ErrorWithContextParams(ctx, "server returned an unexpected status code")
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength+1))
 	if err != nil {
 		return werror.WrapWithContextParams(ctx, err, "server returned an error and failed to read body")
 	}
+	if len(body) > maxErrorBodyLength {
+		body = body[:maxErrorBodyLength]
+		ctx = wparams.ContextWithSafeParam(ctx, "responseBodyTruncated", true)
+	}
 	if len(body) == 0 {
 		return werror.ErrorWithContextParams(ctx, resp.Status)
 	}
-	var errObj oauth2Error
-	if err := json.Unmarshal(body, &errObj); err != nil {
-		return werror.WrapWithContextParams(ctx, err, "server returned an error and failed to unmarshal body",
-			werror.UnsafeParam("responseBody", string(body)))
-	} else if errObj.ErrorType == "" {
+	contentType := resp.Header.Get("Content-Type")
+	if !isJSONErrorBody(contentType, body) {
+		// gateways and proxies in front of the token endpoint may return HTML or plain text error pages
+		return werror.ErrorWithContextParams(ctx, resp.Status,
+			werror.SafeParam("contentType", contentType),
+			werror.UnsafeParam("responseBody", truncateErrorBody(redactSecrets(string(body), d.secrets))))
+	}
+	errObj, ok := parseOAuthError(body)
+	if !ok {
 		return werror.ErrorWithContextParams(ctx, "server returned an error and failed to unmarshal body",
-			werror.UnsafeParam("responseBody", string(body)))
+			werror.UnsafeParam("responseBody", redactSecrets(string(body), d.secrets)))
+	}
+	errObj.ErrorDescription = redactSecrets(errObj.ErrorDescription, d.secrets)
+	errObj.ErrorURI = redactSecrets(errObj.ErrorURI, d.secrets)
+	return &oauthResponseError{
+		error:    werror.ErrorWithContextParams(ctx, resp.Status, werror.Params(errObj)),
+		oauthErr: errObj,
+	}
+}
+
+// oauthResponseError is the error returned for an RFC 6749 error response. Its message and parameters are those of the
+// wrapped error, whose message is the status of the response as for other error responses, and errors.As also finds
+// the *OAuthError parsed from the body.
+type oauthResponseError struct {
+	error
+	oauthErr *OAuthError
+}
+
+// Cause returns the wrapped error, so that its parameters are found by werror.ParamsFromError.
+func (e *oauthResponseError) Cause() error {
+	return e.error
+}
+
+func (e *oauthResponseError) Unwrap() []error {
+	return []error{e.error, e.oauthErr}
+}
+
+// isJSONErrorBody reports whether an error response with the provided Content-Type and body should be parsed as JSON.
+// Besides JSON media types, bodies that start with an object are parsed, since some token endpoints return JSON error
+// responses with a text/plain or missing Content-Type.
+func isJSONErrorBody(contentType string, body []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return true
+		}
 	}
-	return werror.ErrorWithContextParams(ctx, resp.Status, werror.Params(errObj))
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
 }
 
-// oauth2Error implements the JSON structure defined in RFC 6749 Section 5.2.
+// truncateErrorBody returns at most maxErrorBodySnippetLength bytes of body, followed by "..." if it was truncated.
+func truncateErrorBody(body string) string {
+	if len(body) <= maxErrorBodySnippetLength {
+		return body
+	}
+	// drop a multi-byte character that may have been split
+	return strings.ToValidUTF8(body[:maxErrorBodySnippetLength], "") + "..."
+}
+
+// parseOAuthError extracts an RFC 6749 error response from body. It returns false if body is not a JSON object with a
+// non-empty string "error" member. Members that are missing or are not strings are ignored, as are unknown members.
+func parseOAuthError(body []byte) (*OAuthError, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, false
+	}
+	stringField := func(name string) string {
+		var value string
+		if raw, ok := fields[name]; ok {
+			_ = json.Unmarshal(raw, &value)
+		}
+		return value
+	}
+	errObj := &OAuthError{
+		ErrorType:        stringField("error"),
+		ErrorDescription: stringField("error_description"),
+		ErrorURI:         stringField("error_uri"),
+	}
+	if errObj.ErrorType == "" {
+		return nil, false
+	}
+	return errObj, true
+}
+
+// OAuthError is an error response returned by an OAuth2 server, which implements the JSON structure defined in
+// RFC 6749 Section 5.2. Errors returned by the clients in this package for such responses wrap an *OAuthError, so
+// callers can use errors.As to branch on ErrorType, for example to treat "invalid_client" as a terminal failure.
 // https://datatracker.ietf.org/doc/html/rfc6749#section-5.2
-type oauth2Error struct {
-	ErrorType        string `json:"error"`
+type OAuthError struct {
+	// ErrorType is the error code, such as "invalid_grant".
+	ErrorType string `json:"error"`
+	// ErrorDescription is a human-readable description of the error. It may include user data, so it is not included
+	// in the message returned by Error.
 	ErrorDescription string `json:"error_description"`
 	ErrorURI         string `json:"error_uri"`
 }
 
-func (e oauth2Error) SafeParams() map[string]interface{} {
+// Error returns the error code.
+func (e *OAuthError) Error() string {
+	return e.ErrorType
+}
+
+func (e *OAuthError) SafeParams() map[string]interface{} {
 	return map[string]interface{}{"oauthError": e.ErrorType}
 }
 
-func (e oauth2Error) UnsafeParams() map[string]interface{} {
+func (e *OAuthError) UnsafeParams() map[string]interface{} {
 	m := map[string]interface{}{}
 	if e.ErrorDescription != "" {
 		m["oauthErrorDescription"] = e.ErrorDescription
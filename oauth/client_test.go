@@ -16,9 +16,11 @@ package oauth
 
 import (
 	"context"
+	stderrors "errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
@@ -66,6 +68,13 @@ func TestClient(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "token", token)
 	})
+	t.Run("response", func(t *testing.T) {
+		resp, err := tokenClient.CreateClientCredentialTokenResponse(ctx, userName, userSecret)
+		require.NoError(t, err)
+		assert.Equal(t, &TokenResponse{AccessToken: "token"}, resp)
+		_, err = tokenClient.CreateClientCredentialTokenResponse(ctx, "bad-user", "bad-secret")
+		require.EqualError(t, err, "failed to make create client credential token request: httpclient request failed: 400 Bad Request")
+	})
 	t.Run("error", func(t *testing.T) {
 		token, err := badTokenProvider(ctx)
 		assert.Empty(t, token)
@@ -74,6 +83,17 @@ func TestClient(t *testing.T) {
 		assert.EqualValues(t, 400, safe["statusCode"])
 		assert.EqualValues(t, "invalid_client", safe["oauthError"])
 		assert.EqualValues(t, "Client authentication failed", unsafe["oauthErrorDescription"])
+
+		var oauthErr *OAuthError
+		require.True(t, stderrors.As(err, &oauthErr))
+		assert.Equal(t, &OAuthError{ErrorType: "invalid_client", ErrorDescription: "Client authentication failed"}, oauthErr)
+	})
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, ValidateClientCredentials(ctx, tokenClient, userName, userSecret))
+		err := ValidateClientCredentials(ctx, tokenClient, "bad-user", "bad-secret")
+		require.Error(t, err)
+		safe, _ := werror.ParamsFromError(err)
+		assert.EqualValues(t, "invalid_client", safe["oauthError"])
 	})
 	// Use client as a token provider for a different API expecting a bearer token
 	t.Run("TokenProvider", func(t *testing.T) {
@@ -138,3 +158,322 @@ func TestClient(t *testing.T) {
 		})
 	})
 }
+
+func TestWithSuccessStatusCodes(t *testing.T) {
+	ctx := context.Background()
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusCreated)
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	t.Run("default", func(t *testing.T) {
+		token, err := NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(ctx, "user", "secret")
+		require.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+	t.Run("201 accepted", func(t *testing.T) {
+		token, err := NewClientCredentialClient(tokenHTTPClient, WithSuccessStatusCodes(http.StatusOK, http.StatusCreated)).
+			CreateClientCredentialToken(ctx, "user", "secret")
+		require.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+	t.Run("201 rejected", func(t *testing.T) {
+		_, err := NewClientCredentialClient(tokenHTTPClient, WithSuccessStatusCodes(http.StatusOK)).
+			CreateClientCredentialToken(ctx, "user", "secret")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server returned an unexpected status code")
+		safe, _ := werror.ParamsFromError(err)
+		assert.EqualValues(t, http.StatusCreated, safe["statusCode"])
+	})
+}
+
+func TestParseOAuthError(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		body   string
+		want   *OAuthError
+		wantOK bool
+	}{
+		{
+			name:   "full",
+			body:   `{"error":"invalid_client","error_description":"Client authentication failed","error_uri":"https://example.com"}`,
+			want:   &OAuthError{ErrorType: "invalid_client", ErrorDescription: "Client authentication failed", ErrorURI: "https://example.com"},
+			wantOK: true,
+		},
+		{
+			name:   "extra and wrongly typed fields",
+			body:   `{"error":"invalid_grant","error_description":42,"extra":{"a":1}}`,
+			want:   &OAuthError{ErrorType: "invalid_grant"},
+			wantOK: true,
+		},
+		{name: "missing error", body: `{"error_description":"Client authentication failed"}`},
+		{name: "error is not a string", body: `{"error":1}`},
+		{name: "truncated", body: `{"error":"invalid_cl`},
+		{name: "not an object", body: `["invalid_client"]`},
+		{name: "html", body: `<html><body>Bad Gateway</body></html>`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseOAuthError([]byte(tc.body))
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNonJSONErrorBody(t *testing.T) {
+	ctx := context.Background()
+	htmlPage := "<html><head><title>502 Bad Gateway</title></head><body>" + strings.Repeat("nginx ", 500) + "</body></html>"
+	for _, tc := range []struct {
+		name            string
+		statusCode      int
+		contentType     string
+		body            string
+		wantMessage     string
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "html",
+			statusCode:      http.StatusBadGateway,
+			contentType:     "text/html",
+			body:            htmlPage,
+			wantMessage:     "502 Bad Gateway",
+			wantContentType: "text/html",
+			wantBody:        htmlPage[:maxErrorBodySnippetLength] + "...",
+		},
+		{
+			name:            "plain text",
+			statusCode:      http.StatusServiceUnavailable,
+			contentType:     "text/plain; charset=utf-8",
+			body:            "upstream connect error or disconnect/reset before headers",
+			wantMessage:     "503 Service Unavailable",
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "upstream connect error or disconnect/reset before headers",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", tc.contentType)
+				rw.WriteHeader(tc.statusCode)
+				_, err := rw.Write([]byte(tc.body))
+				assert.NoError(t, err)
+			}))
+			defer tokenSrv.Close()
+			tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}), httpclient.WithMaxRetries(0))
+			require.NoError(t, err)
+
+			_, err = NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(ctx, "user", "secret")
+			require.EqualError(t, err, "failed to make create client credential token request: httpclient request failed: "+tc.wantMessage)
+			safe, unsafe := werror.ParamsFromError(err)
+			assert.EqualValues(t, tc.statusCode, safe["statusCode"])
+			assert.Equal(t, tc.wantContentType, safe["contentType"])
+			assert.Equal(t, tc.wantBody, unsafe["responseBody"])
+			var oauthErr *OAuthError
+			assert.False(t, stderrors.As(err, &oauthErr))
+		})
+	}
+}
+
+func TestLargeErrorBody(t *testing.T) {
+	ctx := context.Background()
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusBadRequest)
+		_, err := rw.Write([]byte(`{"error":"invalid_request","error_description":"` + strings.Repeat("a", 1<<20) + `"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	_, err = NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(ctx, "user", "secret")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server returned an error and failed to unmarshal body")
+	safe, unsafe := werror.ParamsFromError(err)
+	assert.Equal(t, true, safe["responseBodyTruncated"])
+	assert.Len(t, unsafe["responseBody"], maxErrorBodyLength)
+}
+
+func FuzzParseOAuthError(f *testing.F) {
+	f.Add([]byte(`{"error":"invalid_client","error_description":"Client authentication failed"}`))
+	f.Add([]byte(`{"error":1,"error_uri":null}`))
+	f.Add([]byte(`{"error":"invalid_cl`))
+	f.Add([]byte(`<html></html>`))
+	f.Add([]byte(``))
+	f.Fuzz(func(t *testing.T, body []byte) {
+		errObj, ok := parseOAuthError(body)
+		if ok {
+			assert.NotEmpty(t, errObj.ErrorType)
+		} else {
+			assert.Nil(t, errObj)
+		}
+	})
+}
+
+func TestWithContentType(t *testing.T) {
+	var gotContentType string
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotContentType = req.Header.Get("Content-Type")
+		body := url.Values{}
+		assert.NoError(t, codecs.FormURLEncoded.Decode(req.Body, &body))
+		assert.Equal(t, "user", body.Get("client_id"))
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	_, err = NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(context.Background(), "user", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-www-form-urlencoded", gotContentType)
+
+	const contentType = "application/x-www-form-urlencoded; charset=UTF-8"
+	_, err = NewClientCredentialClient(tokenHTTPClient, WithContentType(contentType)).
+		CreateClientCredentialToken(context.Background(), "user", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, contentType, gotContentType)
+}
+
+func TestRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	wantSecret := []string{"secret"}
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body := url.Values{}
+		assert.NoError(t, codecs.FormURLEncoded.Decode(req.Body, &body))
+		assert.Equal(t, "refresh_token", body.Get("grant_type"))
+		assert.Equal(t, "id", body.Get("client_id"))
+		assert.Equal(t, wantSecret, body["client_secret"])
+		if body.Get("refresh_token") != "refresh-1" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err := rw.Write([]byte(`{"error":"invalid_grant"}`))
+			assert.NoError(t, err)
+			return
+		}
+		_, err := rw.Write([]byte(`{"access_token":"token","token_type":"bearer","expires_in":60,"refresh_token":"refresh-2"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+	tokenClient := NewClientCredentialClient(tokenHTTPClient)
+
+	token, err := tokenClient.RefreshToken(ctx, "id", "secret", "refresh-1")
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	resp, err := tokenClient.RefreshTokenResponse(ctx, "id", "secret", "refresh-1")
+	require.NoError(t, err)
+	assert.Equal(t, &TokenResponse{
+		AccessToken:  "token",
+		TokenType:    "Bearer",
+		ExpiresIn:    60,
+		RefreshToken: "refresh-2",
+	}, resp)
+
+	_, err = tokenClient.RefreshToken(ctx, "id", "secret", "revoked")
+	require.EqualError(t, err, "failed to make refresh token request: httpclient request failed: 400 Bad Request")
+	safe, _ := werror.ParamsFromError(err)
+	assert.Equal(t, "invalid_grant", safe["oauthError"])
+
+	// public clients send only their client ID
+	wantSecret = nil
+	token, err = NewClientCredentialClient(tokenHTTPClient, WithClientAuthMethod(ClientAuthMethodNone)).
+		RefreshToken(ctx, "id", "", "refresh-1")
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+}
+
+func TestRequestParameterOptions(t *testing.T) {
+	var gotBody url.Values
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotBody = url.Values{}
+		assert.NoError(t, codecs.FormURLEncoded.Decode(req.Body, &gotBody))
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name  string
+		param string
+		opt   ClientOption
+		want  []string
+	}{
+		{name: "WithScopes", param: "scope", opt: WithScopes("read", "write"), want: []string{"read write"}},
+		{name: "WithAudience", param: "audience", opt: WithAudience("https://api.example.com"), want: []string{"https://api.example.com"}},
+		{
+			name:  "WithResources",
+			param: "resource",
+			opt:   WithResources("https://api.example.com", "https://other.example.com/v1"),
+			want:  []string{"https://api.example.com", "https://other.example.com/v1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(context.Background(), "user", "secret")
+			require.NoError(t, err)
+			assert.NotContains(t, gotBody, tc.param)
+
+			_, err = NewClientCredentialClient(tokenHTTPClient, tc.opt).CreateClientCredentialToken(context.Background(), "user", "secret")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, gotBody[tc.param])
+		})
+	}
+}
+
+func TestCreateJWTBearerToken(t *testing.T) {
+	ctx := context.Background()
+	var gotBody url.Values
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotBody = url.Values{}
+		assert.NoError(t, codecs.FormURLEncoded.Decode(req.Body, &gotBody))
+		if gotBody.Get("assertion") != "signed-jwt" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err := rw.Write([]byte(`{"error":"invalid_grant"}`))
+			assert.NoError(t, err)
+			return
+		}
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+	tokenClient := NewClientCredentialClient(tokenHTTPClient).(JWTBearerClient)
+
+	token, err := tokenClient.CreateJWTBearerToken(ctx, "signed-jwt")
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+	assert.Equal(t, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {"signed-jwt"},
+	}, gotBody)
+
+	_, err = tokenClient.CreateJWTBearerToken(ctx, "expired-jwt")
+	require.EqualError(t, err, "failed to make create JWT bearer token request: httpclient request failed: 400 Bad Request")
+}
+
+func TestEmptyAccessToken(t *testing.T) {
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, err := rw.Write([]byte(`{"token_type":"bearer","scope":"read"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	token, err := NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(context.Background(), "user", "secret")
+	assert.Empty(t, token)
+	require.EqualError(t, err, "failed to make create client credential token request: token endpoint returned a response without an access token")
+	safe, _ := werror.ParamsFromError(err)
+	assert.Equal(t, "/oauth2/token", safe["endpoint"])
+	assert.Equal(t, "Bearer", safe["tokenType"])
+	assert.Equal(t, "read", safe["scope"])
+}
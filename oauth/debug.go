@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+// WithDebugLogging configures the client to log each request to the authorization server at debug level using the
+// svc1log.Logger of the request context. The request endpoint and form parameters are logged before the request is
+// sent, and the response status and any OAuth error fields after it completes. The values of parameters that hold
+// credentials or tokens, such as client_secret and code_verifier, are redacted, and tokens from responses are never
+// logged.
+func WithDebugLogging() ClientOption {
+	return func(s *serviceClient) {
+		s.debug = true
+	}
+}
+
+// logRequest logs a request to path with the form values urlValues if debug logging is enabled.
+func (s *serviceClient) logRequest(ctx context.Context, rpcName, path string, urlValues url.Values) {
+	if !s.debug {
+		return
+	}
+	keys := make([]string, 0, len(urlValues))
+	for key := range urlValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	svc1log.FromContext(ctx).Debug("Sending OAuth2 request.",
+		svc1log.SafeParam("endpoint", path),
+		svc1log.SafeParam("rpcName", rpcName),
+		svc1log.SafeParam("formKeys", keys),
+		svc1log.UnsafeParam("form", redactFormValues(urlValues)))
+}
+
+// logResponse logs the outcome of a request to path if debug logging is enabled.
+func (s *serviceClient) logResponse(ctx context.Context, rpcName, path string, resp *http.Response, err error) {
+	if !s.debug {
+		return
+	}
+	params := []svc1log.Param{
+		svc1log.SafeParam("endpoint", path),
+		svc1log.SafeParam("rpcName", rpcName),
+	}
+	if resp != nil {
+		params = append(params, svc1log.SafeParam("statusCode", resp.StatusCode))
+	} else if statusCode, _ := werror.ParamFromError(err, "statusCode"); statusCode != nil {
+		params = append(params, svc1log.SafeParam("statusCode", statusCode))
+	}
+	var oauthErr *OAuthError
+	if errors.As(err, &oauthErr) {
+		params = append(params,
+			svc1log.SafeParams(oauthErr.SafeParams()),
+			svc1log.UnsafeParams(oauthErr.UnsafeParams()))
+	}
+	if err != nil {
+		params = append(params, svc1log.Stacktrace(err))
+	}
+	svc1log.FromContext(ctx).Debug("Received OAuth2 response.", params...)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/witchcraft-go-logging/wlog"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDebugLogging(t *testing.T) {
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if err := req.ParseForm(); assert.NoError(t, err) && req.Form.Get("client_secret") != "s3cr3t" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, err = rw.Write([]byte(`{"error":"invalid_client","error_description":"bad credentials"}`))
+			assert.NoError(t, err)
+			return
+		}
+		_, err := rw.Write([]byte(`{"access_token":"t0k3n"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+	tokenClient := NewClientCredentialClient(tokenHTTPClient, WithDebugLogging())
+
+	logger := &recordingLogger{}
+	ctx := svc1log.WithLogger(context.Background(), logger)
+	_, err = tokenClient.CreateClientCredentialToken(ctx, "id", "s3cr3t")
+	require.NoError(t, err)
+	_, err = tokenClient.CreateClientCredentialToken(ctx, "id", "wr0ng")
+	require.Error(t, err)
+
+	require.Len(t, logger.entries, 4)
+	assert.Equal(t, "/oauth2/token", logger.entries[0]["endpoint"])
+	assert.Equal(t, []string{"client_id", "client_secret", "grant_type"}, logger.entries[0]["formKeys"])
+	assert.Equal(t, map[string][]string{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {"id"},
+		"client_secret": {"[REDACTED]"},
+	}, logger.entries[0]["form"])
+	assert.Equal(t, http.StatusOK, logger.entries[1]["statusCode"])
+	assert.Equal(t, http.StatusUnauthorized, logger.entries[3]["statusCode"])
+	assert.Equal(t, "invalid_client", logger.entries[3]["oauthError"])
+	assert.Equal(t, "bad credentials", logger.entries[3]["oauthErrorDescription"])
+	for _, entry := range logger.entries {
+		for _, secret := range []string{"s3cr3t", "wr0ng", "t0k3n"} {
+			assert.NotContains(t, fmt.Sprint(entry), secret)
+		}
+	}
+}
+
+// recordingLogger is a svc1log.Logger that records the safe and unsafe parameters of debug log entries.
+type recordingLogger struct {
+	entries []map[string]interface{}
+}
+
+func (l *recordingLogger) Debug(_ string, params ...svc1log.Param) {
+	entry := wlog.NewMapLogEntry()
+	for _, p := range params {
+		svc1log.ApplyParam(p, entry)
+	}
+	values := map[string]interface{}{}
+	for _, m := range entry.AnyMapValues() {
+		for k, v := range m {
+			values[k] = v
+		}
+	}
+	l.entries = append(l.entries, values)
+}
+
+func (l *recordingLogger) Info(string, ...svc1log.Param)  {}
+func (l *recordingLogger) Warn(string, ...svc1log.Param)  {}
+func (l *recordingLogger) Error(string, ...svc1log.Param) {}
+func (l *recordingLogger) SetLevel(wlog.LogLevel)         {}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"os"
+	"strings"
+
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// Environment variables read by ClientCredentialsFromEnv.
+const (
+	EnvClientID         = "OAUTH2_CLIENT_ID"
+	EnvClientSecret     = "OAUTH2_CLIENT_SECRET"
+	EnvClientSecretFile = "OAUTH2_CLIENT_SECRET_FILE"
+	EnvTokenURL         = "OAUTH2_TOKEN_URL"
+	EnvScopes           = "OAUTH2_SCOPES"
+)
+
+// ClientCredentials are the credentials of an OAuth2 client along with optional settings for its token requests.
+type ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+	// TokenURL is the URL of the token endpoint, or empty if it was not provided.
+	TokenURL string
+	// Scopes are the scopes to request, or empty if none were provided.
+	Scopes []string
+}
+
+// ClientCredentialsFromEnv reads client credentials from the environment.
+//
+// The client ID is read from OAUTH2_CLIENT_ID. The client secret is read from OAUTH2_CLIENT_SECRET or, if that
+// variable is unset or empty, from the file named by OAUTH2_CLIENT_SECRET_FILE with surrounding whitespace removed.
+// The optional token URL is read from OAUTH2_TOKEN_URL and the optional scopes from the space-delimited
+// OAUTH2_SCOPES. An error naming the missing variables is returned if the client ID or secret is not set. Returned
+// errors never contain the value of the secret.
+func ClientCredentialsFromEnv() (ClientCredentials, error) {
+	creds := ClientCredentials{
+		ClientID:     os.Getenv(EnvClientID),
+		ClientSecret: os.Getenv(EnvClientSecret),
+		TokenURL:     os.Getenv(EnvTokenURL),
+	}
+	if scopes := strings.Fields(os.Getenv(EnvScopes)); len(scopes) > 0 {
+		creds.Scopes = scopes
+	}
+	if creds.ClientSecret == "" {
+		if secretFile := os.Getenv(EnvClientSecretFile); secretFile != "" {
+			secret, err := os.ReadFile(secretFile)
+			if err != nil {
+				return ClientCredentials{}, werror.Wrap(err, "failed to read client secret file",
+					werror.SafeParam("envVar", EnvClientSecretFile))
+			}
+			creds.ClientSecret = strings.TrimSpace(string(secret))
+		}
+	}
+	var missing []string
+	if creds.ClientID == "" {
+		missing = append(missing, EnvClientID)
+	}
+	if creds.ClientSecret == "" {
+		missing = append(missing, EnvClientSecret+" or "+EnvClientSecretFile)
+	}
+	if len(missing) > 0 {
+		return ClientCredentials{}, werror.Error("client credentials are not set in the environment",
+			werror.SafeParam("missing", missing))
+	}
+	return creds, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCredentialsFromEnv(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-secret\n"), 0600))
+
+	for _, tc := range []struct {
+		name    string
+		env     map[string]string
+		want    ClientCredentials
+		wantErr string
+	}{
+		{
+			name: "all variables",
+			env: map[string]string{
+				EnvClientID:     "id",
+				EnvClientSecret: "secret",
+				EnvTokenURL:     "https://example.com/oauth2/token",
+				EnvScopes:       "read  write",
+			},
+			want: ClientCredentials{
+				ClientID:     "id",
+				ClientSecret: "secret",
+				TokenURL:     "https://example.com/oauth2/token",
+				Scopes:       []string{"read", "write"},
+			},
+		},
+		{
+			name: "secret takes precedence over secret file",
+			env: map[string]string{
+				EnvClientID:         "id",
+				EnvClientSecret:     "secret",
+				EnvClientSecretFile: secretFile,
+			},
+			want: ClientCredentials{ClientID: "id", ClientSecret: "secret"},
+		},
+		{
+			name: "secret file",
+			env: map[string]string{
+				EnvClientID:         "id",
+				EnvClientSecretFile: secretFile,
+			},
+			want: ClientCredentials{ClientID: "id", ClientSecret: "file-secret"},
+		},
+		{
+			name:    "missing",
+			env:     map[string]string{},
+			wantErr: "client credentials are not set in the environment",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{EnvClientID, EnvClientSecret, EnvClientSecretFile, EnvTokenURL, EnvScopes} {
+				t.Setenv(key, tc.env[key])
+			}
+			creds, err := ClientCredentialsFromEnv()
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				safe, _ := werror.ParamsFromError(err)
+				assert.Equal(t, []string{EnvClientID, EnvClientSecret + " or " + EnvClientSecretFile}, safe["missing"])
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, creds)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+const tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// Token type identifiers defined in RFC 8693 Section 3.
+const (
+	TokenTypeAccessToken  = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeRefreshToken = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeIDToken      = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeJWT          = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// TokenExchangeClient exchanges tokens using the token exchange grant defined in RFC 8693.
+type TokenExchangeClient interface {
+	ExchangeToken(ctx context.Context, req TokenExchangeRequest) (*TokenExchangeResponse, error)
+}
+
+// TokenExchangeRequest is a token exchange request, as defined in RFC 8693 Section 2.1. SubjectToken and
+// SubjectTokenType are required, as is ActorTokenType if ActorToken is set. Other empty members are omitted.
+// https://datatracker.ietf.org/doc/html/rfc8693#section-2.1
+type TokenExchangeRequest struct {
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	RequestedTokenType string
+	Audience           []string
+	Scopes             []string
+	Resource           []string
+}
+
+// TokenExchangeResponse is a successful token exchange response, as defined in RFC 8693 Section 2.2.1.
+type TokenExchangeResponse struct {
+	TokenResponse
+	IssuedTokenType string `json:"issued_token_type"`
+}
+
+type tokenExchangeClient struct {
+	client       *serviceClient
+	clientID     string
+	clientSecret string
+}
+
+// NewTokenExchangeClient returns a TokenExchangeClient configured using the provided client that authenticates token
+// exchange requests with the provided client credentials.
+// The client will use the httpclient's configured BaseURIs.
+func NewTokenExchangeClient(client httpclient.Client, clientID, clientSecret string, opts ...ClientOption) TokenExchangeClient {
+	return NewTokenExchangeClientWithEndpoint(client, clientCredentialsEndpoint, clientID, clientSecret, opts...)
+}
+
+// NewTokenExchangeClientWithEndpoint returns a TokenExchangeClient configured using the provided client and token
+// endpoint that authenticates token exchange requests with the provided client credentials.
+// The client will use the httpclient's configured BaseURIs.
+func NewTokenExchangeClientWithEndpoint(client httpclient.Client, endpoint, clientID, clientSecret string, opts ...ClientOption) TokenExchangeClient {
+	return &tokenExchangeClient{
+		client:       newServiceClient(client, endpoint, opts),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
+
+func (c *tokenExchangeClient) ExchangeToken(ctx context.Context, req TokenExchangeRequest) (*TokenExchangeResponse, error) {
+	urlValues, err := req.urlValues()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := c.client.clientAuth.apply(ctx, urlValues, c.clientID, c.clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.client.addFormParams(ctx, urlValues); err != nil {
+		return nil, err
+	}
+	var resp TokenExchangeResponse
+	if err := c.client.postForm(ctx, "ExchangeToken", c.client.clientCredentialEndpoint, urlValues, &resp, c.client.responseDecoder, auth); err != nil {
+		return nil, werror.WrapWithContextParams(ctx, err, "failed to make token exchange request")
+	}
+	resp.TokenType = normalizeTokenType(resp.TokenType)
+	return &resp, nil
+}
+
+func (r TokenExchangeRequest) urlValues() (url.Values, error) {
+	if r.SubjectToken == "" || r.SubjectTokenType == "" {
+		return nil, werror.Error("token exchange request requires a subject token and subject token type")
+	}
+	if r.ActorToken != "" && r.ActorTokenType == "" {
+		return nil, werror.Error("token exchange request with an actor token requires an actor token type")
+	}
+	urlValues := url.Values{
+		"grant_type":         []string{tokenExchangeGrantType},
+		"subject_token":      []string{r.SubjectToken},
+		"subject_token_type": []string{r.SubjectTokenType},
+	}
+	if r.ActorToken != "" {
+		urlValues.Set("actor_token", r.ActorToken)
+		urlValues.Set("actor_token_type", r.ActorTokenType)
+	}
+	if r.RequestedTokenType != "" {
+		urlValues.Set("requested_token_type", r.RequestedTokenType)
+	}
+	if len(r.Audience) > 0 {
+		urlValues["audience"] = r.Audience
+	}
+	if len(r.Scopes) > 0 {
+		urlValues.Set("scope", strings.Join(r.Scopes, " "))
+	}
+	if len(r.Resource) > 0 {
+		urlValues["resource"] = r.Resource
+	}
+	return urlValues, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenExchangeClient(t *testing.T) {
+	ctx := context.Background()
+	var gotBody url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotBody = url.Values{}
+		assert.NoError(t, codecs.FormURLEncoded.Decode(req.Body, &gotBody))
+		_, err := rw.Write([]byte(`{"access_token":"downstream","issued_token_type":"urn:ietf:params:oauth:token-type:access_token",` +
+			`"token_type":"bearer","expires_in":60,"scope":"read"}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+	httpClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{srv.URL}))
+	require.NoError(t, err)
+	client := NewTokenExchangeClient(httpClient, "gateway", "secret")
+
+	resp, err := client.ExchangeToken(ctx, TokenExchangeRequest{
+		SubjectToken:     "incoming",
+		SubjectTokenType: TokenTypeAccessToken,
+		ActorToken:       "gateway-token",
+		ActorTokenType:   TokenTypeJWT,
+		Audience:         []string{"downstream"},
+		Scopes:           []string{"read", "write"},
+		Resource:         []string{"https://downstream/api"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &TokenExchangeResponse{
+		TokenResponse: TokenResponse{
+			AccessToken: "downstream",
+			TokenType:   "Bearer",
+			ExpiresIn:   60,
+			Scope:       "read",
+		},
+		IssuedTokenType: TokenTypeAccessToken,
+	}, resp)
+	assert.Equal(t, url.Values{
+		"grant_type":         {"urn:ietf:params:oauth:grant-type:token-exchange"},
+		"subject_token":      {"incoming"},
+		"subject_token_type": {TokenTypeAccessToken},
+		"actor_token":        {"gateway-token"},
+		"actor_token_type":   {TokenTypeJWT},
+		"audience":           {"downstream"},
+		"scope":              {"read write"},
+		"resource":           {"https://downstream/api"},
+		"client_id":          {"gateway"},
+		"client_secret":      {"secret"},
+	}, gotBody)
+
+	_, err = client.ExchangeToken(ctx, TokenExchangeRequest{SubjectToken: "incoming"})
+	require.EqualError(t, err, "token exchange request requires a subject token and subject token type")
+	_, err = client.ExchangeToken(ctx, TokenExchangeRequest{SubjectToken: "incoming", SubjectTokenType: TokenTypeJWT, ActorToken: "actor"})
+	require.EqualError(t, err, "token exchange request with an actor token requires an actor token type")
+}
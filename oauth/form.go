@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+	wparams "github.com/palantir/witchcraft-go-params"
+)
+
+// reservedFormParams are the parameters that identify the grant and authenticate the client, which can never be set
+// using WithFormParams.
+var reservedFormParams = map[string]struct{}{
+	"grant_type":            {},
+	"client_id":             {},
+	"client_secret":         {},
+	"client_assertion":      {},
+	"client_assertion_type": {},
+}
+
+// WithFormParams adds params to the body of every token request, for identity providers that require non-standard
+// parameters such as tenant hints. Parameters set by the client take precedence and are never overridden: a request
+// fails without contacting the server if params contains a reserved parameter (grant_type, client_id, client_secret,
+// client_assertion or client_assertion_type), or a parameter that the request already sets, such as scope when
+// WithScopes is used. Calling WithFormParams more than once adds to the parameters set by previous calls.
+func WithFormParams(params url.Values) ClientOption {
+	return func(s *serviceClient) {
+		if s.formParams == nil {
+			s.formParams = url.Values{}
+		}
+		for key, values := range params {
+			s.formParams[key] = append(s.formParams[key], values...)
+		}
+	}
+}
+
+// addFormParams adds the parameters configured using WithFormParams to urlValues, or returns an error if any of them
+// is reserved or already set.
+func (s *serviceClient) addFormParams(ctx context.Context, urlValues url.Values) error {
+	for key := range s.formParams {
+		_, reserved := reservedFormParams[key]
+		if _, set := urlValues[key]; reserved || set {
+			return werror.ErrorWithContextParams(wparams.ContextWithSafeParam(ctx, "formParam", key),
+				"form parameter cannot be overridden")
+		}
+	}
+	for key, values := range s.formParams {
+		urlValues[key] = append([]string(nil), values...)
+	}
+	return nil
+}
+
+// WithJSONRequestBody configures the client to send the parameters of token requests as a JSON object, for providers
+// that do not accept the form-encoded body required by RFC 6749. Each parameter is a member of the object whose value
+// is a string, or an array of strings for a parameter with several values, such as "resource" when WithResources is
+// used with more than one resource. Client credentials sent using HTTP Basic authentication remain in the
+// Authorization header.
+func WithJSONRequestBody() ClientOption {
+	return func(s *serviceClient) {
+		s.jsonRequestBody = true
+	}
+}
+
+// requestBody returns the body of a request with the parameters urlValues and the encoder for it.
+func (s *serviceClient) requestBody(urlValues url.Values) (interface{}, codecs.Encoder) {
+	if !s.jsonRequestBody {
+		return urlValues, codecs.FormURLEncoded
+	}
+	object := make(map[string]interface{}, len(urlValues))
+	for key, values := range urlValues {
+		if len(values) == 1 {
+			object[key] = values[0]
+		} else {
+			object[key] = values
+		}
+	}
+	return object, codecs.JSON
+}
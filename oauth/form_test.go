@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFormParams(t *testing.T) {
+	ctx := context.Background()
+	var gotBody url.Values
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotBody = url.Values{}
+		assert.NoError(t, codecs.FormURLEncoded.Decode(req.Body, &gotBody))
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	_, err = NewClientCredentialClient(tokenHTTPClient,
+		WithFormParams(url.Values{"tenant": {"acme"}}),
+		WithFormParams(url.Values{"resource": {"https://a", "https://b"}}),
+	).CreateClientCredentialToken(ctx, "id", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {"id"},
+		"client_secret": {"secret"},
+		"tenant":        {"acme"},
+		"resource":      {"https://a", "https://b"},
+	}, gotBody)
+
+	for _, tc := range []struct {
+		name  string
+		opts  []ClientOption
+		param string
+	}{
+		{name: "reserved", opts: []ClientOption{WithFormParams(url.Values{"grant_type": {"password"}})}, param: "grant_type"},
+		{name: "reserved but unset", opts: []ClientOption{
+			WithClientAuthMethod(ClientAuthMethodSecretBasic),
+			WithFormParams(url.Values{"client_id": {"other"}}),
+		}, param: "client_id"},
+		{name: "already set", opts: []ClientOption{WithScopes("read"), WithFormParams(url.Values{"scope": {"write"}})}, param: "scope"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gotBody = nil
+			_, err := NewClientCredentialClient(tokenHTTPClient, tc.opts...).CreateClientCredentialToken(ctx, "id", "secret")
+			require.EqualError(t, err, "failed to make create client credential token request: form parameter cannot be overridden")
+			safe, _ := werror.ParamsFromError(err)
+			assert.Equal(t, tc.param, safe["formParam"])
+			assert.Nil(t, gotBody, "request must not be sent")
+		})
+	}
+}
+
+func TestWithJSONRequestBody(t *testing.T) {
+	ctx := context.Background()
+	var gotContentType string
+	var gotBody map[string]interface{}
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotContentType = req.Header.Get("Content-Type")
+		gotBody = nil
+		assert.NoError(t, codecs.JSON.Decode(req.Body, &gotBody))
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+	stdlibClient, err := NewClientCredentialClientFromHTTP(nil, tokenSrv.URL+"/oauth2/token",
+		WithJSONRequestBody(), WithResources("https://a", "https://b"))
+	require.NoError(t, err)
+
+	for name, client := range map[string]ClientCredentialClient{
+		"httpclient": NewClientCredentialClient(tokenHTTPClient, WithJSONRequestBody(), WithResources("https://a", "https://b")),
+		"stdlib":     stdlibClient,
+	} {
+		t.Run(name, func(t *testing.T) {
+			token, err := client.CreateClientCredentialToken(ctx, "id", "secret")
+			require.NoError(t, err)
+			assert.Equal(t, "token", token)
+			assert.Equal(t, "application/json", gotContentType)
+			assert.Equal(t, map[string]interface{}{
+				"grant_type":    "client_credentials",
+				"client_id":     "id",
+				"client_secret": "secret",
+				"resource":      []interface{}{"https://a", "https://b"},
+			}, gotBody)
+		})
+	}
+}
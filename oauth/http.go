@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// NewClientCredentialClientFromHTTP returns a ClientCredentialClient that sends token requests to tokenURL, the
+// absolute URL of the token endpoint, using httpClient instead of an httpclient.Client. This allows callers that
+// configure a standard library *http.Client, for example with a proxy, timeout or TLS configuration, to use it for
+// token requests. If httpClient is nil, http.DefaultClient is used. A query in tokenURL, such as the policy parameter
+// required by some providers, is sent with every request.
+//
+// Requests and responses are encoded and decoded as by the other constructors, but failed requests are not retried,
+// and the options that configure the transport of the client, such as WithClientCertificate, have no effect.
+func NewClientCredentialClientFromHTTP(httpClient *http.Client, tokenURL string, opts ...ClientOption) (ClientCredentialClient, error) {
+	u, err := url.Parse(tokenURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, werror.Error("token URL must be an absolute URL", werror.UnsafeParam("tokenURL", tokenURL))
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	s := newServiceClient(nil, u.Path, opts)
+	s.httpClient = httpClient
+	s.baseURL = (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+	s.rawQuery = u.RawQuery
+	return s, nil
+}
+
+// requestURL returns the URL of path for requests made using the standard library client of s.
+func (s *serviceClient) requestURL(path string) string {
+	if s.rawQuery == "" {
+		return s.baseURL + path
+	}
+	return s.baseURL + path + "?" + s.rawQuery
+}
+
+// doHTTP posts the form-encoded urlValues to path using the standard library client of s and decodes the response
+// into out using decoder. It returns an error decoded by errorDecoder for a response that it handles.
+func (s *serviceClient) doHTTP(ctx context.Context, path string, urlValues url.Values, out interface{}, decoder codecs.Decoder, auth *basicAuth, errorDecoder errorDecoder) (*http.Response, error) {
+	body, encoder := s.requestBody(urlValues)
+	data, err := encoder.Marshal(body)
+	if err != nil {
+		return nil, werror.WrapWithContextParams(ctx, err, "failed to encode token endpoint request")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.requestURL(path), bytes.NewReader(data))
+	if err != nil {
+		return nil, werror.WrapWithContextParams(ctx, err, "failed to create token endpoint request")
+	}
+	contentType := s.contentType
+	if contentType == "" {
+		contentType = encoder.ContentType()
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", decoder.Accept())
+	if auth != nil {
+		req.SetBasicAuth(auth.username, auth.password)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, werror.WrapWithContextParams(ctx, err, "token endpoint request failed")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if errorDecoder.Handles(resp) {
+		return nil, errorDecoder.DecodeError(resp)
+	}
+	if err := decoder.Decode(resp.Body, out); err != nil {
+		return nil, werror.WrapWithContextParams(ctx, err, "failed to decode token endpoint response")
+	}
+	return resp, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientCredentialClientFromHTTP(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		assert.Equal(t, "/realms/test/protocol/openid-connect/token", req.URL.Path)
+		assert.Equal(t, "custom", req.Header.Get("X-Transport"))
+		assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+		if req.FormValue("client_secret") != "secret" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err := rw.Write([]byte(`{"error":"invalid_client"}`))
+			assert.NoError(t, err)
+			return
+		}
+		_, err := rw.Write([]byte(`{"access_token":"token","expires_in":60}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req.Header.Set("X-Transport", "custom")
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	client, err := NewClientCredentialClientFromHTTP(httpClient, tokenSrv.URL+"/realms/test/protocol/openid-connect/token")
+	require.NoError(t, err)
+	resp, err := client.CreateClientCredentialTokenResponse(ctx, "id", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, &TokenResponse{AccessToken: "token", ExpiresIn: 60}, resp)
+
+	_, err = client.CreateClientCredentialToken(ctx, "id", "wrong")
+	require.EqualError(t, err, "failed to make create client credential token request: 400 Bad Request")
+	var oauthErr *OAuthError
+	assert.True(t, stderrors.As(err, &oauthErr))
+	assert.Equal(t, 2, calls)
+
+	_, err = NewClientCredentialClientFromHTTP(nil, "/oauth2/token")
+	require.EqualError(t, err, "token URL must be an absolute URL")
+}
+
+func TestNewClientCredentialClientFromHTTP_Query(t *testing.T) {
+	var gotQuery url.Values
+	var gotForm url.Values
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotQuery = req.URL.Query()
+		assert.NoError(t, req.ParseForm())
+		gotForm = req.PostForm
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+
+	client, err := NewClientCredentialClientFromHTTP(nil, tokenSrv.URL+"/tenant/oauth2/v2.0/token?p=b2c_1_policy")
+	require.NoError(t, err)
+	_, err = client.CreateClientCredentialToken(context.Background(), "id", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"p": {"b2c_1_policy"}}, gotQuery)
+	assert.Equal(t, "secret", gotForm.Get("client_secret"))
+
+	gotQuery = nil
+	require.NoError(t, client.(TokenEndpointPinger).Ping(context.Background()))
+	assert.Equal(t, url.Values{"p": {"b2c_1_policy"}}, gotQuery)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+const introspectionEndpoint = "/oauth2/introspect"
+
+// IntrospectionClient queries the state of tokens using OAuth2 token introspection, as defined in RFC 7662.
+type IntrospectionClient interface {
+	// Introspect returns the server's information about token. A token that is expired, revoked or otherwise
+	// unusable results in a response with Active set to false rather than an error.
+	Introspect(ctx context.Context, token string) (*IntrospectionResponse, error)
+}
+
+// IntrospectionResponse is a token introspection response, as defined in RFC 7662 Section 2.2. All members other than
+// Active are optional and are only meaningful if Active is true.
+// https://datatracker.ietf.org/doc/html/rfc7662#section-2.2
+type IntrospectionResponse struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope"`
+	ClientID  string   `json:"client_id"`
+	Username  string   `json:"username"`
+	TokenType string   `json:"token_type"`
+	Exp       int64    `json:"exp"`
+	Iat       int64    `json:"iat"`
+	Nbf       int64    `json:"nbf"`
+	Sub       string   `json:"sub"`
+	Aud       Audience `json:"aud"`
+	Iss       string   `json:"iss"`
+	Jti       string   `json:"jti"`
+}
+
+// Audience is the value of an "aud" member, which may be encoded as either a single string or an array of strings.
+// A null value decodes to a nil Audience.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		*a = nil
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return werror.Error("aud is neither a string nor an array of strings")
+	}
+	*a = multiple
+	return nil
+}
+
+type introspectionClient struct {
+	client       *serviceClient
+	endpoint     string
+	clientID     string
+	clientSecret string
+}
+
+// NewIntrospectionClient returns an IntrospectionClient configured using the provided client that authenticates
+// introspection requests with the provided client credentials.
+// The client will use the httpclient's configured BaseURIs.
+func NewIntrospectionClient(client httpclient.Client, clientID, clientSecret string, opts ...ClientOption) IntrospectionClient {
+	return NewIntrospectionClientWithEndpoint(client, introspectionEndpoint, clientID, clientSecret, opts...)
+}
+
+// NewIntrospectionClientWithEndpoint returns an IntrospectionClient configured using the provided client and
+// introspection endpoint that authenticates introspection requests with the provided client credentials.
+// The client will use the httpclient's configured BaseURIs.
+func NewIntrospectionClientWithEndpoint(client httpclient.Client, endpoint, clientID, clientSecret string, opts ...ClientOption) IntrospectionClient {
+	return &introspectionClient{
+		client:       newServiceClient(client, clientCredentialsEndpoint, opts),
+		endpoint:     endpoint,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
+
+func (c *introspectionClient) Introspect(ctx context.Context, token string) (*IntrospectionResponse, error) {
+	urlValues := url.Values{
+		"token": []string{token},
+	}
+	auth, err := c.client.clientAuth.apply(ctx, urlValues, c.clientID, c.clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	var resp IntrospectionResponse
+	if err := c.client.postForm(ctx, "Introspect", c.endpoint, urlValues, &resp, codecs.JSON, auth); err != nil {
+		return nil, werror.WrapWithContextParams(ctx, err, "failed to make token introspection request")
+	}
+	return &resp, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospectionClient(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/oauth2/introspect", req.URL.Path)
+		body := url.Values{}
+		assert.NoError(t, codecs.FormURLEncoded.Decode(req.Body, &body))
+		if body.Get("client_id") != "resource-server" || body.Get("client_secret") != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, err := rw.Write([]byte(`{"error":"invalid_client"}`))
+			assert.NoError(t, err)
+			return
+		}
+		switch body.Get("token") {
+		case "active":
+			_, err := rw.Write([]byte(`{"active":true,"scope":"read write","client_id":"app","username":"user",` +
+				`"token_type":"Bearer","exp":1700000000,"sub":"user-id","aud":"api","iss":"https://idp"}`))
+			assert.NoError(t, err)
+		default:
+			_, err := rw.Write([]byte(`{"active":false}`))
+			assert.NoError(t, err)
+		}
+	}))
+	defer srv.Close()
+	httpClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{srv.URL}))
+	require.NoError(t, err)
+	client := NewIntrospectionClient(httpClient, "resource-server", "secret")
+
+	resp, err := client.Introspect(ctx, "active")
+	require.NoError(t, err)
+	assert.Equal(t, &IntrospectionResponse{
+		Active:    true,
+		Scope:     "read write",
+		ClientID:  "app",
+		Username:  "user",
+		TokenType: "Bearer",
+		Exp:       1700000000,
+		Sub:       "user-id",
+		Aud:       Audience{"api"},
+		Iss:       "https://idp",
+	}, resp)
+
+	resp, err = client.Introspect(ctx, "revoked")
+	require.NoError(t, err)
+	assert.Equal(t, &IntrospectionResponse{}, resp)
+
+	_, err = NewIntrospectionClient(httpClient, "resource-server", "wrong").Introspect(ctx, "active")
+	require.EqualError(t, err, "failed to make token introspection request: httpclient request failed: 401 Unauthorized")
+	safe, _ := werror.ParamsFromError(err)
+	assert.Equal(t, "invalid_client", safe["oauthError"])
+}
+
+func TestAudience(t *testing.T) {
+	var resp IntrospectionResponse
+	require.NoError(t, codecs.JSON.Unmarshal([]byte(`{"aud":["a","b"]}`), &resp))
+	assert.Equal(t, Audience{"a", "b"}, resp.Aud)
+	require.Error(t, codecs.JSON.Unmarshal([]byte(`{"aud":1}`), &resp))
+
+	require.NoError(t, codecs.JSON.Unmarshal([]byte(`{"aud":null}`), &resp))
+	assert.Nil(t, resp.Aud)
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+	wparams "github.com/palantir/witchcraft-go-params"
+)
+
+// JWKS is a JSON Web Key Set, as defined in RFC 7517 Section 5, such as the set of keys a provider uses to sign its
+// ID tokens and JWT access tokens.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-5
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a public JSON Web Key, as defined in RFC 7517 Section 4. The members of RSA and elliptic curve keys, which
+// are defined in RFC 7518 Section 6, are decoded into fields; other key types can be identified by KeyType but cannot
+// be converted using PublicKey.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-4
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// N and E are the base64url-encoded modulus and exponent of an RSA key.
+	N string `json:"n"`
+	E string `json:"e"`
+	// Curve, X and Y are the curve and base64url-encoded coordinates of an elliptic curve key.
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+// Key returns the key with the provided key ID, which is typically the "kid" header of the JWT being verified.
+func (s *JWKS) Key(keyID string) (JWK, bool) {
+	for _, key := range s.Keys {
+		if key.KeyID == keyID {
+			return key, true
+		}
+	}
+	return JWK{}, false
+}
+
+// PublicKey returns k as an *rsa.PublicKey or an *ecdsa.PublicKey, for use with a JWT library that verifies
+// signatures. An error is returned for other key types and for keys with missing or malformed members.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, werror.Wrap(err, "invalid RSA key modulus", werror.SafeParam("kid", k.KeyID))
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, werror.Error("invalid RSA key exponent", werror.SafeParam("kid", k.KeyID))
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, werror.Error("unsupported elliptic curve", werror.SafeParam("kid", k.KeyID), werror.SafeParam("crv", k.Curve))
+		}
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, werror.Wrap(err, "invalid elliptic curve key x coordinate", werror.SafeParam("kid", k.KeyID))
+		}
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, werror.Wrap(err, "invalid elliptic curve key y coordinate", werror.SafeParam("kid", k.KeyID))
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, werror.Error("unsupported key type", werror.SafeParam("kid", k.KeyID), werror.SafeParam("kty", k.KeyType))
+	}
+}
+
+// decodeJWKInt decodes a base64url-encoded unsigned big-endian integer member of a JWK.
+func decodeJWKInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, werror.Error("member is missing")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, werror.Wrap(err, "member is not base64url-encoded")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// FetchJWKS retrieves the key set at path using client. path is relative to the httpclient's configured BaseURIs, and
+// an error is returned if it is an absolute URL. To fetch the jwks_uri advertised in a provider's OpenID Connect
+// discovery document, configure client with the scheme and host of that URL and pass its path.
+func FetchJWKS(ctx context.Context, client httpclient.Client, path string) (*JWKS, error) {
+	jwks, _, err := fetchJWKS(ctx, client, path)
+	return jwks, err
+}
+
+// fetchJWKS retrieves the key set at path and returns it with the duration for which it may be cached.
+func fetchJWKS(ctx context.Context, client httpclient.Client, path string) (*JWKS, time.Duration, error) {
+	ctx = wparams.ContextWithSafeParam(ctx, "path", path)
+	if u, err := url.Parse(path); err != nil || u.Scheme != "" || u.Host != "" {
+		return nil, 0, werror.ErrorWithContextParams(ctx, "JWKS path must be relative to the base URLs of the client")
+	}
+	var jwks JWKS
+	resp, err := client.Do(ctx,
+		httpclient.WithRPCMethodName("FetchJWKS"),
+		httpclient.WithRequestMethod(http.MethodGet),
+		httpclient.WithPath(path),
+		httpclient.WithResponseBody(&jwks, codecs.JSON),
+		httpclient.WithRequestErrorDecoder(errorDecoder{ctx: ctx}),
+	)
+	if err != nil {
+		return nil, 0, werror.WrapWithContextParams(ctx, err, "failed to fetch JWKS")
+	}
+	return &jwks, cacheMaxAge(resp.Header.Get("Cache-Control")), nil
+}
+
+// cacheMaxAge returns the max-age directive of cacheControl, or 0 if it is missing or the response must not be reused
+// without revalidation.
+func cacheMaxAge(cacheControl string) time.Duration {
+	var maxAge time.Duration
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil || seconds < 0 {
+				return 0
+			}
+			maxAge = time.Duration(seconds) * time.Second
+		}
+	}
+	return maxAge
+}
+
+// JWKSCache serves the key set at a path, refetching it once the max-age of the Cache-Control header of the previous
+// response has elapsed. A key set served without a max-age, or with no-cache or no-store, is refetched on every call.
+// It is safe for concurrent use; at most one fetch is in progress at a time.
+type JWKSCache struct {
+	client httpclient.Client
+	path   string
+
+	mu      sync.Mutex
+	jwks    *JWKS
+	expires time.Time
+	// fetching, if non-nil, is closed once the fetch in progress completes.
+	fetching chan struct{}
+	// fetchErr is the error of the last fetch, if it failed.
+	fetchErr error
+}
+
+// NewJWKSCache returns a JWKSCache that fetches the key set at path using client, as described for FetchJWKS. path is
+// relative to the httpclient's configured BaseURIs.
+func NewJWKSCache(client httpclient.Client, path string) *JWKSCache {
+	return &JWKSCache{
+		client: client,
+		path:   path,
+	}
+}
+
+// JWKS returns the cached key set, fetching it if the cached key set has expired. If a key set has been fetched
+// before, it is returned while another caller is refetching it and if refetching fails, so that an outage of the JWKS
+// endpoint does not prevent tokens signed with known keys from being verified. An error is returned only if no key set
+// has been fetched successfully.
+func (c *JWKSCache) JWKS(ctx context.Context) (*JWKS, error) {
+	c.mu.Lock()
+	if c.jwks != nil && (c.fetching != nil || time.Now().Before(c.expires)) {
+		jwks := c.jwks
+		c.mu.Unlock()
+		return jwks, nil
+	}
+	if fetching := c.fetching; fetching != nil {
+		// no key set is cached, so wait for the fetch in progress
+		c.mu.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, werror.WrapWithContextParams(ctx, ctx.Err(), "context done while waiting for JWKS")
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.jwks == nil {
+			return nil, c.fetchErr
+		}
+		return c.jwks, nil
+	}
+	fetching := make(chan struct{})
+	c.fetching = fetching
+	c.mu.Unlock()
+
+	jwks, maxAge, err := fetchJWKS(ctx, c.client, c.path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetching = nil
+	c.fetchErr = err
+	close(fetching)
+	if err != nil {
+		if c.jwks == nil {
+			return nil, err
+		}
+		return c.jwks, nil
+	}
+	c.jwks = jwks
+	c.expires = time.Now().Add(maxAge)
+	return jwks, nil
+}
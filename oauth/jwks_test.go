@@ -0,0 +1,201 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchJWKS(t *testing.T) {
+	ctx := context.Background()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+	body, err := json.Marshal(JWKS{Keys: []JWK{
+		{KeyType: "RSA", KeyID: "rsa-key", Use: "sig", Algorithm: "RS256", N: encode(rsaKey.N), E: encode(big.NewInt(int64(rsaKey.E)))},
+		{KeyType: "EC", KeyID: "ec-key", Curve: "P-256", X: encode(ecKey.X), Y: encode(ecKey.Y)},
+		{KeyType: "OKP", KeyID: "okp-key", Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}})
+	require.NoError(t, err)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/.well-known/jwks.json" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := rw.Write(body)
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+	httpClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{srv.URL}))
+	require.NoError(t, err)
+
+	jwks, err := FetchJWKS(ctx, httpClient, "/.well-known/jwks.json")
+	require.NoError(t, err)
+	require.Len(t, jwks.Keys, 3)
+
+	key, ok := jwks.Key("rsa-key")
+	require.True(t, ok)
+	assert.Equal(t, "RS256", key.Algorithm)
+	publicKey, err := key.PublicKey()
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(publicKey))
+
+	key, ok = jwks.Key("ec-key")
+	require.True(t, ok)
+	publicKey, err = key.PublicKey()
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(publicKey))
+
+	key, ok = jwks.Key("okp-key")
+	require.True(t, ok)
+	_, err = key.PublicKey()
+	require.EqualError(t, err, "unsupported key type")
+
+	_, ok = jwks.Key("unknown")
+	assert.False(t, ok)
+
+	_, err = FetchJWKS(ctx, httpClient, "/missing")
+	require.Error(t, err)
+	safe, _ := werror.ParamsFromError(err)
+	assert.EqualValues(t, http.StatusNotFound, safe["statusCode"])
+	assert.Equal(t, "/missing", safe["path"])
+
+	for _, absoluteURL := range []string{srv.URL + "/.well-known/jwks.json", "//127.0.0.1/.well-known/jwks.json"} {
+		_, err = FetchJWKS(ctx, httpClient, absoluteURL)
+		require.EqualError(t, err, "JWKS path must be relative to the base URLs of the client", absoluteURL)
+		_, err = NewJWKSCache(httpClient, absoluteURL).JWKS(ctx)
+		require.EqualError(t, err, "JWKS path must be relative to the base URLs of the client", absoluteURL)
+	}
+}
+
+func TestJWKSCache(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	var cacheControl string
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.Header().Set("Cache-Control", cacheControl)
+		_, err := rw.Write([]byte(`{"keys":[{"kty":"EC","kid":"key"}]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+	httpClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{srv.URL}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		cacheControl string
+		wantCalls    int
+	}{
+		{cacheControl: "public, max-age=3600", wantCalls: 1},
+		{cacheControl: "max-age=3600, no-cache", wantCalls: 3},
+		{cacheControl: "no-store", wantCalls: 3},
+		{cacheControl: "", wantCalls: 3},
+	} {
+		t.Run(tc.cacheControl, func(t *testing.T) {
+			calls, cacheControl = 0, tc.cacheControl
+			cache := NewJWKSCache(httpClient, "/jwks")
+			for i := 0; i < 3; i++ {
+				jwks, err := cache.JWKS(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, &JWKS{Keys: []JWK{{KeyType: "EC", KeyID: "key"}}}, jwks)
+			}
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func TestJWKSCacheServesStaleKeySet(t *testing.T) {
+	ctx := context.Background()
+	var failing int32
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			received <- struct{}{}
+			<-release
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, err := rw.Write([]byte(`{"keys":[{"kty":"EC","kid":"key"}]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+	httpClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{srv.URL}), httpclient.WithMaxRetries(0))
+	require.NoError(t, err)
+	want := &JWKS{Keys: []JWK{{KeyType: "EC", KeyID: "key"}}}
+
+	cache := NewJWKSCache(httpClient, "/jwks")
+	jwks, err := cache.JWKS(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, jwks)
+
+	atomic.StoreInt32(&failing, 1)
+	refetched := make(chan error, 1)
+	go func() {
+		jwks, err := cache.JWKS(ctx)
+		assert.Equal(t, want, jwks)
+		refetched <- err
+	}()
+	<-received
+	// the refetch is blocked, and the previous key set is served meanwhile
+	jwks, err = cache.JWKS(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, jwks)
+	// the refetch fails, and the previous key set is still served
+	close(release)
+	require.NoError(t, <-refetched)
+
+	// no key set has been fetched, so the error is returned
+	_, err = NewJWKSCache(httpClient, "/jwks").JWKS(ctx)
+	require.Error(t, err)
+	safe, _ := werror.ParamsFromError(err)
+	assert.EqualValues(t, http.StatusServiceUnavailable, safe["statusCode"])
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	for cacheControl, want := range map[string]time.Duration{
+		"max-age=60":                  time.Minute,
+		"public, MAX-AGE=\"60\"":      time.Minute,
+		"max-age=60, must-revalidate": time.Minute,
+		"max-age=-1":                  0,
+		"max-age=abc":                 0,
+		"no-cache, max-age=60":        0,
+		"private":                     0,
+		"":                            0,
+	} {
+		assert.Equal(t, want, cacheMaxAge(cacheControl), cacheControl)
+	}
+}
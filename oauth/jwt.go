@@ -0,0 +1,179 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strings"
+
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+const actorClaim = "act"
+
+// Actor is an entry in the delegation chain described by the "act" claim of a JWT, as defined by RFC 8693 section
+// 4.1. Actor is the party that acted before this one, if any, so following it walks the chain from the current actor
+// to the earliest.
+type Actor struct {
+	Subject string
+	// Claims holds the claims identifying the actor other than "sub" and "act", such as "iss".
+	Claims map[string]interface{}
+	Actor  *Actor
+}
+
+// ActorClaim returns the delegation chain described by the "act" claim of accessToken. The token's signature is not
+// verified, so the result must only be used for auditing tokens that have been validated by other means.
+// ok is false if accessToken is not a JWT, such as an opaque token, or if it has no "act" claim. An error is returned
+// if the claim is present but is not a JSON object.
+func ActorClaim(accessToken string) (actor *Actor, ok bool, err error) {
+	claims, ok := decodeJWTClaims(accessToken)
+	if !ok {
+		return nil, false, nil
+	}
+	raw, ok := claims[actorClaim]
+	if !ok {
+		return nil, false, nil
+	}
+	actor, err = parseActor(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	return actor, true, nil
+}
+
+func parseActor(raw json.RawMessage) (*Actor, error) {
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &claims); err != nil || claims == nil {
+		return nil, werror.Error("act claim is not a JSON object")
+	}
+	actor := &Actor{Claims: make(map[string]interface{})}
+	for name, value := range claims {
+		switch name {
+		case "sub":
+			if err := json.Unmarshal(value, &actor.Subject); err != nil {
+				return nil, werror.Error("act claim has a non-string sub")
+			}
+		case actorClaim:
+			prior, err := parseActor(value)
+			if err != nil {
+				return nil, err
+			}
+			actor.Actor = prior
+		default:
+			var v interface{}
+			if err := json.Unmarshal(value, &v); err != nil {
+				return nil, werror.Wrap(err, "failed to unmarshal act claim")
+			}
+			actor.Claims[name] = v
+		}
+	}
+	return actor, nil
+}
+
+// IDTokenClaims holds the claims of an OpenID Connect ID token. The standard claims most commonly used by clients are
+// decoded into fields, and Claims holds every claim, including those. ExpiresAt and IssuedAt are in seconds since the
+// epoch; a non-integer NumericDate is truncated.
+// https://openid.net/specs/openid-connect-core-1_0.html#IDToken
+type IDTokenClaims struct {
+	Issuer    string                 `json:"iss"`
+	Subject   string                 `json:"sub"`
+	Audience  Audience               `json:"aud"`
+	ExpiresAt int64                  `json:"exp"`
+	IssuedAt  int64                  `json:"iat"`
+	Nonce     string                 `json:"nonce"`
+	Email     string                 `json:"email"`
+	Name      string                 `json:"name"`
+	Claims    map[string]interface{} `json:"-"`
+}
+
+func (c *IDTokenClaims) UnmarshalJSON(data []byte) error {
+	type claims IDTokenClaims
+	aux := struct {
+		*claims
+		ExpiresAt numericDate `json:"exp"`
+		IssuedAt  numericDate `json:"iat"`
+	}{claims: (*claims)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	c.ExpiresAt = int64(aux.ExpiresAt)
+	c.IssuedAt = int64(aux.IssuedAt)
+	return nil
+}
+
+// numericDate is a JSON NumericDate, the number of seconds since the epoch, which RFC 7519 Section 2 allows to be
+// non-integer. The fractional part is discarded.
+// https://datatracker.ietf.org/doc/html/rfc7519#section-2
+type numericDate int64
+
+func (d *numericDate) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil || seconds < math.MinInt64 || seconds >= math.MaxInt64 {
+		return werror.Error("NumericDate is not a number of seconds")
+	}
+	*d = numericDate(seconds)
+	return nil
+}
+
+// ParseIDTokenClaims decodes the claims of idToken, such as the IDToken of a TokenResponse. The token's signature is
+// not verified, so the claims must not be trusted unless the token was received directly from the token endpoint
+// over TLS.
+func ParseIDTokenClaims(idToken string) (*IDTokenClaims, error) {
+	payload, ok := decodeJWTPayload(idToken)
+	if !ok {
+		return nil, werror.Error("ID token is not a JWT")
+	}
+	var claims IDTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, werror.Wrap(err, "failed to unmarshal ID token claims")
+	}
+	if err := json.Unmarshal(payload, &claims.Claims); err != nil {
+		return nil, werror.Wrap(err, "failed to unmarshal ID token claims")
+	}
+	return &claims, nil
+}
+
+// decodeJWTClaims returns the claims of the payload of a JWS compact serialization without verifying its signature.
+// ok is false if token is not of that form.
+func decodeJWTClaims(token string) (claims map[string]json.RawMessage, ok bool) {
+	payload, ok := decodeJWTPayload(token)
+	if !ok {
+		return nil, false
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims == nil {
+		return nil, false
+	}
+	return claims, true
+}
+
+// decodeJWTPayload returns the payload of a JWS compact serialization without verifying its signature. ok is false if
+// token is not of that form or its payload is not a JSON object.
+func decodeJWTPayload(token string) (payload []byte, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, false
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &object); err != nil || object == nil {
+		return nil, false
+	}
+	return payload, true
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorClaim(t *testing.T) {
+	newJWT := func(payload string) string {
+		return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		actor, ok, err := ActorClaim(newJWT(`{"sub":"user","act":{"sub":"gateway","iss":"https://idp","act":{"sub":"frontend"}}}`))
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, &Actor{
+			Subject: "gateway",
+			Claims:  map[string]interface{}{"iss": "https://idp"},
+			Actor: &Actor{
+				Subject: "frontend",
+				Claims:  map[string]interface{}{},
+			},
+		}, actor)
+	})
+	for name, token := range map[string]string{
+		"opaque":       "2YotnFZFEjr1zCsicMWpAA",
+		"no act claim": newJWT(`{"sub":"user"}`),
+		"bad payload":  "a.!!!.c",
+		"non-JSON":     newJWT(`not json`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			actor, ok, err := ActorClaim(token)
+			require.NoError(t, err)
+			assert.False(t, ok)
+			assert.Nil(t, actor)
+		})
+	}
+	t.Run("malformed act claim", func(t *testing.T) {
+		_, ok, err := ActorClaim(newJWT(`{"sub":"user","act":"gateway"}`))
+		require.EqualError(t, err, "act claim is not a JSON object")
+		assert.False(t, ok)
+	})
+}
+
+func TestParseIDTokenClaims(t *testing.T) {
+	payload := `{"iss":"https://idp","sub":"user-id","aud":"app","exp":1700000000,"iat":1690000000,"email":"user@example.com","name":"User","groups":["admin"]}`
+	claims, err := ParseIDTokenClaims("eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig")
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp", claims.Issuer)
+	assert.Equal(t, "user-id", claims.Subject)
+	assert.Equal(t, Audience{"app"}, claims.Audience)
+	assert.EqualValues(t, 1700000000, claims.ExpiresAt)
+	assert.EqualValues(t, 1690000000, claims.IssuedAt)
+	assert.Equal(t, "user@example.com", claims.Email)
+	assert.Equal(t, "User", claims.Name)
+	assert.Equal(t, []interface{}{"admin"}, claims.Claims["groups"])
+	assert.Equal(t, "user-id", claims.Claims["sub"])
+
+	claims, err = ParseIDTokenClaims("eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-id","aud":null}`)) + ".sig")
+	require.NoError(t, err)
+	assert.Nil(t, claims.Audience)
+
+	claims, err = ParseIDTokenClaims("eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-id","exp":1700000000.5,"iat":1.69e9}`)) + ".sig")
+	require.NoError(t, err)
+	assert.Equal(t, "user-id", claims.Subject)
+	assert.EqualValues(t, 1700000000, claims.ExpiresAt)
+	assert.EqualValues(t, 1690000000, claims.IssuedAt)
+	assert.Equal(t, 1700000000.5, claims.Claims["exp"])
+
+	_, err = ParseIDTokenClaims("eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(`{"exp":"tomorrow"}`)) + ".sig")
+	require.EqualError(t, err, "failed to unmarshal ID token claims: NumericDate is not a number of seconds")
+
+	_, err = ParseIDTokenClaims("opaque")
+	require.EqualError(t, err, "ID token is not a JWT")
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+)
+
+const (
+	// RequestTimerName is the name of the timer that records the duration of requests to the authorization server.
+	RequestTimerName = "oauth2.client.request"
+	// RequestResultMeterName is the name of the meter that records the outcome of requests to the authorization server.
+	RequestResultMeterName = "oauth2.client.request.result"
+
+	endpointTagKey = "endpoint"
+	resultTagKey   = "result"
+)
+
+// WithMetrics configures the client to record the duration of each request to the authorization server in the
+// RequestTimerName timer and its outcome in the RequestResultMeterName meter. Both are tagged with the endpoint of the
+// request, such as "CreateClientCredentialToken", and the meter is additionally tagged with a result of "success" or
+// "failure". Metrics are registered on the registry of the request context, as returned by metrics.FromContext.
+func WithMetrics() ClientOption {
+	return func(s *serviceClient) {
+		s.metrics = true
+	}
+}
+
+// recordRequest records the duration and outcome of a request to the endpoint identified by rpcName if metrics are
+// enabled.
+func (s *serviceClient) recordRequest(ctx context.Context, rpcName string, start time.Time, err error) {
+	if !s.metrics {
+		return
+	}
+	registry := metrics.FromContext(ctx)
+	endpointTag := metrics.NewTagWithFallbackValue(endpointTagKey, rpcName, "unknown")
+	registry.Timer(RequestTimerName, endpointTag).UpdateSince(start)
+	registry.Meter(RequestResultMeterName, endpointTag, resultTag(err)).Mark(1)
+}
+
+// resultTag returns the tag recorded with the outcome of a request that returned err.
+func resultTag(err error) metrics.Tag {
+	if err != nil {
+		return metrics.MustNewTag(resultTagKey, "failure")
+	}
+	return metrics.MustNewTag(resultTagKey, "success")
+}
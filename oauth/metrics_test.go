@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMetrics(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	ctx := metrics.WithRegistry(context.Background(), registry)
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if err := req.ParseForm(); assert.NoError(t, err) && req.Form.Get("client_secret") != "secret" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err = rw.Write([]byte(`{"error":"invalid_client"}`))
+			assert.NoError(t, err)
+			return
+		}
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	_, err = NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(ctx, "id", "secret")
+	require.NoError(t, err)
+	endpointTag := metrics.MustNewTag("endpoint", "CreateClientCredentialToken")
+	assert.Zero(t, registry.Timer(RequestTimerName, endpointTag).Count(), "metrics are only recorded when enabled")
+
+	tokenClient := NewClientCredentialClient(tokenHTTPClient, WithMetrics())
+	_, err = tokenClient.CreateClientCredentialToken(ctx, "id", "secret")
+	require.NoError(t, err)
+	_, err = tokenClient.CreateClientCredentialToken(ctx, "id", "bad-secret")
+	require.Error(t, err)
+
+	assert.EqualValues(t, 2, registry.Timer(RequestTimerName, endpointTag).Count())
+	assert.EqualValues(t, 1, registry.Meter(RequestResultMeterName, endpointTag, metrics.MustNewTag("result", "success")).Count())
+	assert.EqualValues(t, 1, registry.Meter(RequestResultMeterName, endpointTag, metrics.MustNewTag("result", "failure")).Count())
+}
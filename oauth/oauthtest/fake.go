@@ -0,0 +1,149 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oauthtest provides test doubles for the interfaces of the oauth package.
+package oauthtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/palantir/go-oauth2-client/v2/oauth"
+)
+
+// Method names recorded in Call.Method.
+const (
+	MethodCreateClientCredentialToken         = "CreateClientCredentialToken"
+	MethodCreateClientCredentialTokenResponse = "CreateClientCredentialTokenResponse"
+	MethodRefreshToken                        = "RefreshToken"
+	MethodRefreshTokenResponse                = "RefreshTokenResponse"
+	MethodCreateJWTBearerToken                = "CreateJWTBearerToken"
+	MethodPing                                = "Ping"
+)
+
+// Call is a call received by a FakeClientCredentialClient. Arguments that do not apply to Method are empty.
+type Call struct {
+	Method       string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+	Assertion    string
+}
+
+// Responder returns the response of a FakeClientCredentialClient to call.
+type Responder func(ctx context.Context, call Call) (*oauth.TokenResponse, error)
+
+// FakeClientCredentialClient is an oauth.ClientCredentialClient and oauth.JWTBearerClient that returns programmed
+// responses without contacting a server and records the calls it receives. It is safe for concurrent use.
+type FakeClientCredentialClient struct {
+	mu        sync.Mutex
+	responder Responder
+	calls     []Call
+}
+
+var (
+	_ oauth.ClientCredentialClient = (*FakeClientCredentialClient)(nil)
+	_ oauth.JWTBearerClient        = (*FakeClientCredentialClient)(nil)
+	_ oauth.TokenEndpointPinger    = (*FakeClientCredentialClient)(nil)
+)
+
+// NewFakeClientCredentialClient returns a FakeClientCredentialClient whose methods return accessToken.
+func NewFakeClientCredentialClient(accessToken string) *FakeClientCredentialClient {
+	f := &FakeClientCredentialClient{}
+	f.SetResponse(&oauth.TokenResponse{AccessToken: accessToken, TokenType: "Bearer"}, nil)
+	return f
+}
+
+// SetResponse configures every method to return resp, or err if it is non-nil. Methods that return a token string
+// return resp.AccessToken.
+func (f *FakeClientCredentialClient) SetResponse(resp *oauth.TokenResponse, err error) {
+	f.SetResponder(func(context.Context, Call) (*oauth.TokenResponse, error) {
+		if err != nil {
+			return nil, err
+		}
+		respCopy := *resp
+		return &respCopy, nil
+	})
+}
+
+// SetError configures every method to return err.
+func (f *FakeClientCredentialClient) SetError(err error) {
+	f.SetResponse(nil, err)
+}
+
+// SetResponder configures every method to return the result of responder, which can vary the response based on the
+// call, for example to fail for an unknown client ID.
+func (f *FakeClientCredentialClient) SetResponder(responder Responder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responder = responder
+}
+
+// Calls returns the calls received so far, in the order they were made.
+func (f *FakeClientCredentialClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Reset discards the recorded calls.
+func (f *FakeClientCredentialClient) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+func (f *FakeClientCredentialClient) CreateClientCredentialToken(ctx context.Context, clientID, clientSecret string) (string, error) {
+	return accessToken(f.respond(ctx, Call{Method: MethodCreateClientCredentialToken, ClientID: clientID, ClientSecret: clientSecret}))
+}
+
+func (f *FakeClientCredentialClient) CreateClientCredentialTokenResponse(ctx context.Context, clientID, clientSecret string) (*oauth.TokenResponse, error) {
+	return f.respond(ctx, Call{Method: MethodCreateClientCredentialTokenResponse, ClientID: clientID, ClientSecret: clientSecret})
+}
+
+func (f *FakeClientCredentialClient) RefreshToken(ctx context.Context, clientID, clientSecret, refreshToken string) (string, error) {
+	return accessToken(f.respond(ctx, Call{Method: MethodRefreshToken, ClientID: clientID, ClientSecret: clientSecret, RefreshToken: refreshToken}))
+}
+
+func (f *FakeClientCredentialClient) RefreshTokenResponse(ctx context.Context, clientID, clientSecret, refreshToken string) (*oauth.TokenResponse, error) {
+	return f.respond(ctx, Call{Method: MethodRefreshTokenResponse, ClientID: clientID, ClientSecret: clientSecret, RefreshToken: refreshToken})
+}
+
+func (f *FakeClientCredentialClient) CreateJWTBearerToken(ctx context.Context, assertion string) (string, error) {
+	return accessToken(f.respond(ctx, Call{Method: MethodCreateJWTBearerToken, Assertion: assertion}))
+}
+
+// Ping returns the error of the configured response.
+func (f *FakeClientCredentialClient) Ping(ctx context.Context) error {
+	_, err := f.respond(ctx, Call{Method: MethodPing})
+	return err
+}
+
+func (f *FakeClientCredentialClient) respond(ctx context.Context, call Call) (*oauth.TokenResponse, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	responder := f.responder
+	f.mu.Unlock()
+	if responder == nil {
+		return &oauth.TokenResponse{}, nil
+	}
+	return responder(ctx, call)
+}
+
+func accessToken(resp *oauth.TokenResponse, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	return resp.AccessToken, nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauthtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/palantir/go-oauth2-client/v2/oauth"
+	"github.com/palantir/go-oauth2-client/v2/oauth/oauthtest"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClientCredentialClient(t *testing.T) {
+	ctx := context.Background()
+	client := oauthtest.NewFakeClientCredentialClient("token")
+
+	tok, err := client.CreateClientCredentialToken(ctx, "id", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "token", tok)
+	tok, err = client.RefreshToken(ctx, "id", "secret", "refresh")
+	require.NoError(t, err)
+	assert.Equal(t, "token", tok)
+	assert.Equal(t, []oauthtest.Call{
+		{Method: oauthtest.MethodCreateClientCredentialToken, ClientID: "id", ClientSecret: "secret"},
+		{Method: oauthtest.MethodRefreshToken, ClientID: "id", ClientSecret: "secret", RefreshToken: "refresh"},
+	}, client.Calls())
+
+	client.Reset()
+	client.SetResponse(&oauth.TokenResponse{AccessToken: "other", ExpiresIn: 60}, nil)
+	resp, err := client.CreateClientCredentialTokenResponse(ctx, "id", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, &oauth.TokenResponse{AccessToken: "other", ExpiresIn: 60}, resp)
+
+	client.SetError(werror.Error("invalid_client"))
+	_, err = client.CreateJWTBearerToken(ctx, "assertion")
+	require.EqualError(t, err, "invalid_client")
+	require.EqualError(t, client.Ping(ctx), "invalid_client")
+	assert.Equal(t, []oauthtest.Call{
+		{Method: oauthtest.MethodCreateClientCredentialTokenResponse, ClientID: "id", ClientSecret: "secret"},
+		{Method: oauthtest.MethodCreateJWTBearerToken, Assertion: "assertion"},
+		{Method: oauthtest.MethodPing},
+	}, client.Calls())
+}
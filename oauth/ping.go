@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	werror "github.com/palantir/witchcraft-go-error"
+	wparams "github.com/palantir/witchcraft-go-params"
+)
+
+// TokenEndpointPinger checks that an endpoint of an OAuth2 server is reachable without requesting a token, for use in
+// readiness checks. The clients returned by the constructors of this package implement it, so callers can obtain one
+// using a type assertion, such as client.(oauth.TokenEndpointPinger).
+type TokenEndpointPinger interface {
+	// Ping sends an OPTIONS request to the endpoint and returns an error only if the request could not be sent, for
+	// example because the connection was refused, or the server responded with a 5xx status code. A 4xx response
+	// counts as reachable, since it shows that the server is up even if it does not support OPTIONS.
+	Ping(ctx context.Context) error
+}
+
+var (
+	_ TokenEndpointPinger = (*serviceClient)(nil)
+	_ TokenEndpointPinger = (*introspectionClient)(nil)
+	_ TokenEndpointPinger = (*tokenExchangeClient)(nil)
+)
+
+func (s *serviceClient) Ping(ctx context.Context) error {
+	return s.ping(ctx, s.clientCredentialEndpoint)
+}
+
+func (c *introspectionClient) Ping(ctx context.Context) error {
+	return c.client.ping(ctx, c.endpoint)
+}
+
+func (c *tokenExchangeClient) Ping(ctx context.Context) error {
+	return c.client.ping(ctx, c.client.clientCredentialEndpoint)
+}
+
+// ping sends an OPTIONS request to path, which does not request a token, and returns an error if the request could not
+// be sent or the server responded with a 5xx status code. Any other response, including a 4xx response from a server
+// that does not support OPTIONS, shows that the endpoint is reachable.
+func (s *serviceClient) ping(ctx context.Context, path string) error {
+	ctx = wparams.ContextWithSafeParam(ctx, "endpoint", path)
+	var statusCode int
+	var err error
+	if s.httpClient != nil {
+		statusCode, err = s.pingHTTP(ctx, path)
+	} else {
+		_, err = s.client.Do(ctx,
+			httpclient.WithRPCMethodName("Ping"),
+			httpclient.WithRequestMethod(http.MethodOptions),
+			httpclient.WithPath(path),
+		)
+		if code, ok := httpclient.StatusCodeFromError(err); ok && code < http.StatusInternalServerError {
+			err = nil
+		}
+	}
+	if err != nil {
+		return werror.WrapWithContextParams(ctx, err, "token endpoint is unreachable")
+	}
+	if statusCode >= http.StatusInternalServerError {
+		return werror.ErrorWithContextParams(ctx, "token endpoint is unreachable", werror.SafeParam("statusCode", statusCode))
+	}
+	return nil
+}
+
+// pingHTTP sends an OPTIONS request to path using the standard library client of s and returns the status code of the
+// response.
+func (s *serviceClient) pingHTTP(ctx context.Context, path string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, s.requestURL(path), nil)
+	if err != nil {
+		return 0, werror.WrapWithContextParams(ctx, err, "failed to create token endpoint request")
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyLength))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPing(t *testing.T) {
+	ctx := context.Background()
+	var statusCode int
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodOptions, req.Method)
+		assert.Equal(t, "/oauth2/token", req.URL.Path)
+		rw.WriteHeader(statusCode)
+	}))
+	defer tokenSrv.Close()
+	downSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	downURL := downSrv.URL
+	downSrv.Close()
+
+	newClients := func(t *testing.T, baseURL string) map[string]TokenEndpointPinger {
+		httpClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{baseURL}), httpclient.WithMaxRetries(0))
+		require.NoError(t, err)
+		stdlibClient, err := NewClientCredentialClientFromHTTP(nil, baseURL+"/oauth2/token")
+		require.NoError(t, err)
+		return map[string]TokenEndpointPinger{
+			"httpclient":    NewClientCredentialClient(httpClient).(TokenEndpointPinger),
+			"stdlib":        stdlibClient.(TokenEndpointPinger),
+			"introspection": NewIntrospectionClientWithEndpoint(httpClient, "/oauth2/token", "id", "secret").(TokenEndpointPinger),
+			"exchange":      NewTokenExchangeClient(httpClient, "id", "secret").(TokenEndpointPinger),
+		}
+	}
+
+	for name, client := range newClients(t, tokenSrv.URL) {
+		t.Run(name, func(t *testing.T) {
+			for _, statusCode = range []int{http.StatusOK, http.StatusNoContent, http.StatusMethodNotAllowed, http.StatusUnauthorized} {
+				assert.NoError(t, client.Ping(ctx), "status %d", statusCode)
+			}
+			statusCode = http.StatusInternalServerError
+			err := client.Ping(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "token endpoint is unreachable")
+			safe, _ := werror.ParamsFromError(err)
+			assert.EqualValues(t, http.StatusInternalServerError, safe["statusCode"])
+		})
+	}
+	for name, client := range newClients(t, downURL) {
+		t.Run(name+"/connection refused", func(t *testing.T) {
+			err := client.Ping(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "token endpoint is unreachable")
+		})
+	}
+}
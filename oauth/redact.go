@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redacted replaces sensitive values in logs and error parameters.
+const redacted = "[REDACTED]"
+
+// sensitiveFormParams are the form parameters whose values are credentials or tokens. Their values must never be
+// logged or attached to errors, even as unsafe parameters.
+var sensitiveFormParams = map[string]struct{}{
+	"client_secret":    {},
+	"client_assertion": {},
+	"assertion":        {},
+	"code":             {},
+	"code_verifier":    {},
+	"refresh_token":    {},
+	"subject_token":    {},
+	"actor_token":      {},
+	"token":            {},
+	"password":         {},
+}
+
+// isSensitiveFormParam returns whether the value of the form parameter key is a credential or token.
+func isSensitiveFormParam(key string) bool {
+	_, ok := sensitiveFormParams[key]
+	return ok
+}
+
+// redactFormValues returns a copy of values in which the values of sensitive parameters are redacted.
+func redactFormValues(values url.Values) map[string][]string {
+	out := make(map[string][]string, len(values))
+	for key, vals := range values {
+		if isSensitiveFormParam(key) {
+			vals = []string{redacted}
+		}
+		out[key] = append([]string(nil), vals...)
+	}
+	return out
+}
+
+// sensitiveValues returns the values of the sensitive parameters in values.
+func sensitiveValues(values url.Values) []string {
+	var secrets []string
+	for key, vals := range values {
+		if isSensitiveFormParam(key) {
+			secrets = append(secrets, vals...)
+		}
+	}
+	return secrets
+}
+
+// redactSecrets returns s with every occurrence of the provided secrets redacted. It is applied to content returned by
+// the server, such as error descriptions, which may echo parameters of the request.
+func redactSecrets(s string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorParamsRedactSecrets(t *testing.T) {
+	ctx := context.Background()
+	const secret = "s3cr3t-value"
+	var respondJSON bool
+	// the server echoes the request, including credentials, in its error response
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		echo := string(body) + " " + req.Header.Get("Authorization")
+		rw.WriteHeader(http.StatusBadRequest)
+		if respondJSON {
+			_, err = fmt.Fprintf(rw, `{"error":"invalid_request","error_description":%q,"error_uri":"https://example.com/?secret=%s"}`, echo, secret)
+		} else {
+			_, err = rw.Write([]byte("bad request: " + echo))
+		}
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		request func(client ClientCredentialClient) error
+	}{
+		{
+			name: "client_secret",
+			request: func(client ClientCredentialClient) error {
+				_, err := client.CreateClientCredentialToken(ctx, "id", secret)
+				return err
+			},
+		},
+		{
+			name: "client_secret_basic",
+			request: func(client ClientCredentialClient) error {
+				_, err := NewClientCredentialClient(tokenHTTPClient, WithClientAuthMethod(ClientAuthMethodSecretBasic)).
+					CreateClientCredentialToken(ctx, "id", secret)
+				return err
+			},
+		},
+		{
+			name: "refresh_token",
+			request: func(client ClientCredentialClient) error {
+				_, err := client.RefreshToken(ctx, "id", "client-secret", secret)
+				return err
+			},
+		},
+		{
+			name: "assertion",
+			request: func(client ClientCredentialClient) error {
+				_, err := client.(JWTBearerClient).CreateJWTBearerToken(ctx, secret)
+				return err
+			},
+		},
+	} {
+		for _, respondJSON = range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s/json=%v", tc.name, respondJSON), func(t *testing.T) {
+				err := tc.request(NewClientCredentialClient(tokenHTTPClient))
+				require.Error(t, err)
+				safe, unsafe := werror.ParamsFromError(err)
+				assert.NotContains(t, fmt.Sprint(safe), secret)
+				assert.NotContains(t, fmt.Sprint(unsafe), secret)
+				assert.NotContains(t, err.Error(), secret)
+				if respondJSON {
+					assert.Contains(t, fmt.Sprint(unsafe), "https://example.com/?secret="+redacted)
+				}
+			})
+		}
+	}
+}
+
+func TestErrorParamsRedactSecrets_ErrorType(t *testing.T) {
+	ctx := context.Background()
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusTooManyRequests)
+		_, err := rw.Write([]byte(`{"error":"slow_down","error_description":"rate limited"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}), httpclient.WithMaxRetries(0))
+	require.NoError(t, err)
+
+	// the error code is a registered value rather than server-supplied text, so it is never redacted
+	_, err = NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(ctx, "id", "s")
+	var oauthErr *OAuthError
+	require.True(t, stderrors.As(err, &oauthErr))
+	assert.Equal(t, "slow_down", oauthErr.ErrorType)
+	assert.Equal(t, "rate limited", oauthErr.ErrorDescription)
+}
+
+func TestErrorParamsRedactSecrets_BasicAuth(t *testing.T) {
+	ctx := context.Background()
+	// the secret contains characters that are form-encoded before it is used for HTTP Basic authentication
+	const secret = "s3cr3t/value+1"
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, password, ok := req.BasicAuth()
+		assert.True(t, ok)
+		unescaped, err := url.QueryUnescape(password)
+		assert.NoError(t, err)
+		rw.WriteHeader(http.StatusBadRequest)
+		_, err = fmt.Fprintf(rw, "invalid secret %s (encoded as %s)", unescaped, password)
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	_, err = NewClientCredentialClient(tokenHTTPClient, WithClientAuthMethod(ClientAuthMethodSecretBasic)).
+		CreateClientCredentialToken(ctx, "id", secret)
+	require.Error(t, err)
+	_, unsafe := werror.ParamsFromError(err)
+	assert.Equal(t, "invalid secret "+redacted+" (encoded as "+redacted+")", unsafe["responseBody"])
+}
+
+func TestRedactFormValues(t *testing.T) {
+	assert.Equal(t, map[string][]string{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {"id"},
+		"client_secret": {redacted},
+		"code":          {redacted},
+		"code_verifier": {redacted},
+		"refresh_token": {redacted},
+	}, redactFormValues(map[string][]string{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {"id"},
+		"client_secret": {"secret"},
+		"code":          {"code"},
+		"code_verifier": {"verifier"},
+		"refresh_token": {"refresh"},
+	}))
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// tokenTypeBearer is the canonical spelling of the bearer token type defined by RFC 6750.
+const tokenTypeBearer = "Bearer"
+
+// normalizeTokenType returns the canonical spelling of tokenType. Token types are case-insensitive (RFC 6749
+// Section 5.1), so providers variously return "Bearer", "bearer" or "BEARER"; all of them are normalized to "Bearer".
+// Other token types are returned unchanged.
+func normalizeTokenType(tokenType string) string {
+	if strings.EqualFold(tokenType, tokenTypeBearer) {
+		return tokenTypeBearer
+	}
+	return tokenType
+}
+
+// WithAllowAnyTokenType disables the check that token responses contain a bearer token. By default, a response whose
+// token_type is neither empty nor "Bearer", such as "DPoP" or "mac", is rejected with an error, since the token
+// providers and transports of this module send tokens as bearer tokens. Callers that handle other token types
+// themselves can use this option and inspect TokenResponse.TokenType.
+func WithAllowAnyTokenType() ClientOption {
+	return func(s *serviceClient) {
+		s.allowAnyTokenType = true
+	}
+}
+
+// Scopes returns the scopes granted by the server, parsed from Scope. RFC 6749 delimits scopes with spaces, but commas
+// are also accepted, since some providers use them. Empty and duplicate scopes are omitted. Scopes returns nil if the
+// server did not return a scope, which RFC 6749 Section 5.1 permits when the granted scope is the requested one.
+func (r TokenResponse) Scopes() []string {
+	var scopes []string
+	seen := make(map[string]struct{})
+	for _, scope := range strings.FieldsFunc(r.Scope, isScopeDelimiter) {
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+// MissingScopes returns the scopes in requested that the server did not grant, in the order they were requested, or nil
+// if every requested scope was granted. Callers can use it to decide whether a token with a narrower scope than
+// requested is sufficient. If the server did not return a scope, the requested scopes are assumed to have been granted,
+// as RFC 6749 Section 5.1 specifies, and nil is returned.
+func (r TokenResponse) MissingScopes(requested ...string) []string {
+	granted := r.Scopes()
+	if granted == nil {
+		return nil
+	}
+	grantedSet := make(map[string]struct{}, len(granted))
+	for _, scope := range granted {
+		grantedSet[scope] = struct{}{}
+	}
+	var missing []string
+	for _, scope := range requested {
+		if _, ok := grantedSet[scope]; !ok {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+func isScopeDelimiter(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
+// ExpiresInDuration returns the lifetime of the access token. It returns 0 if the server did not provide expires_in,
+// or provided a value that is not positive, in which case callers should treat the lifetime as unknown rather than as
+// already expired.
+func (r TokenResponse) ExpiresInDuration() time.Duration {
+	if r.ExpiresIn <= 0 {
+		return 0
+	}
+	return time.Duration(r.ExpiresIn) * time.Second
+}
+
+// ExpiryTime returns the time at which the access token expires, given that the response was received at now. It
+// returns the zero time if the lifetime of the token is unknown; see ExpiresInDuration.
+func (r TokenResponse) ExpiryTime(now time.Time) time.Time {
+	expiresIn := r.ExpiresInDuration()
+	if expiresIn == 0 {
+		return time.Time{}
+	}
+	return now.Add(expiresIn)
+}
+
+// WithResponseDecoder configures the decoder of successful token responses, for providers whose responses do not have
+// the shape defined by RFC 6749 Section 5.1, such as providers that nest the token in a "data" object. decoder is
+// called with a *TokenResponse, or a *TokenExchangeResponse for token exchange requests, and must populate its
+// standard fields. By default, responses are decoded using codecs.JSON. WithResponseDecoder and WithResponseFieldNames
+// replace each other, so only the last of them takes effect.
+func WithResponseDecoder(decoder codecs.Decoder) ClientOption {
+	return func(s *serviceClient) {
+		if decoder != nil {
+			s.responseDecoder = decoder
+		}
+	}
+}
+
+// ResponseFieldNames are the JSON field names a provider uses in its token response. Fields that are left empty use
+// the name defined by RFC 6749 Section 5.1.
+type ResponseFieldNames struct {
+	AccessToken  string
+	TokenType    string
+	ExpiresIn    string
+	RefreshToken string
+	Scope        string
+}
+
+// WithResponseFieldNames configures the client to read token responses from providers that do not use the field names
+// defined by RFC 6749, such as "accessToken" instead of "access_token". If a response contains both the standard and
+// the configured name for a field, the value of the configured name is used.
+func WithResponseFieldNames(names ResponseFieldNames) ClientOption {
+	return func(s *serviceClient) {
+		s.responseDecoder = fieldNameDecoder{
+			names: map[string]string{
+				"access_token":  names.AccessToken,
+				"token_type":    names.TokenType,
+				"expires_in":    names.ExpiresIn,
+				"refresh_token": names.RefreshToken,
+				"scope":         names.Scope,
+			},
+		}
+	}
+}
+
+// fieldNameDecoder is a JSON decoder that renames fields of a token response to their standard names before
+// unmarshaling it.
+type fieldNameDecoder struct {
+	// names maps each standard field name to the name used by the provider.
+	names map[string]string
+}
+
+func (fieldNameDecoder) Accept() string {
+	return codecs.JSON.Accept()
+}
+
+func (d fieldNameDecoder) Decode(r io.Reader, v interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return werror.Wrap(err, "failed to read token response")
+	}
+	return d.Unmarshal(data, v)
+}
+
+func (d fieldNameDecoder) Unmarshal(data []byte, v interface{}) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return werror.Wrap(err, "failed to unmarshal token response")
+	}
+	for standardName, name := range d.names {
+		if name == "" || name == standardName {
+			continue
+		}
+		if value, ok := fields[name]; ok {
+			fields[standardName] = value
+		}
+	}
+	renamed, err := json.Marshal(fields)
+	if err != nil {
+		return werror.Wrap(err, "failed to marshal renamed token response")
+	}
+	return codecs.JSON.Unmarshal(renamed, v)
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithResponseFieldNames(t *testing.T) {
+	camelCaseNames := ResponseFieldNames{
+		AccessToken:  "accessToken",
+		TokenType:    "tokenType",
+		ExpiresIn:    "expiresIn",
+		RefreshToken: "refreshToken",
+	}
+
+	t.Run("decoder", func(t *testing.T) {
+		s := &serviceClient{}
+		WithResponseFieldNames(camelCaseNames)(s)
+
+		var resp TokenResponse
+		err := s.responseDecoder.Unmarshal([]byte(`{"accessToken":"token","tokenType":"Bearer","expiresIn":3600,"refreshToken":"refresh","scope":"a b"}`), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, TokenResponse{
+			AccessToken:  "token",
+			TokenType:    "Bearer",
+			ExpiresIn:    3600,
+			RefreshToken: "refresh",
+			Scope:        "a b",
+		}, resp)
+	})
+
+	t.Run("client", func(t *testing.T) {
+		tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			_, err := rw.Write([]byte(`{"accessToken":"token","expiresIn":3600}`))
+			assert.NoError(t, err)
+		}))
+		defer tokenSrv.Close()
+
+		tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+		require.NoError(t, err)
+		token, err := NewClientCredentialClient(tokenHTTPClient, WithResponseFieldNames(camelCaseNames)).
+			CreateClientCredentialToken(context.Background(), "user", "secret")
+		require.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+}
+
+func TestWithResponseDecoder(t *testing.T) {
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, err := rw.Write([]byte(`{"status":"ok","data":{"access_token":"token","token_type":"bearer","expires_in":3600}}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	resp, err := NewClientCredentialClient(tokenHTTPClient, WithResponseDecoder(dataEnvelopeDecoder{})).
+		CreateClientCredentialTokenResponse(context.Background(), "user", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, &TokenResponse{AccessToken: "token", TokenType: "Bearer", ExpiresIn: 3600}, resp)
+
+	_, err = NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialTokenResponse(context.Background(), "user", "secret")
+	require.EqualError(t, err, "failed to make create client credential token request: token endpoint returned a response without an access token")
+}
+
+// dataEnvelopeDecoder decodes JSON values nested in the "data" member of a response.
+type dataEnvelopeDecoder struct{}
+
+func (dataEnvelopeDecoder) Accept() string {
+	return codecs.JSON.Accept()
+}
+
+func (d dataEnvelopeDecoder) Decode(r io.Reader, v interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return d.Unmarshal(data, v)
+}
+
+func (dataEnvelopeDecoder) Unmarshal(data []byte, v interface{}) error {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+	return codecs.JSON.Unmarshal(envelope.Data, v)
+}
+
+func TestNormalizeTokenType(t *testing.T) {
+	for in, want := range map[string]string{
+		"Bearer": "Bearer",
+		"bearer": "Bearer",
+		"BEARER": "Bearer",
+		"bEaReR": "Bearer",
+		"DPoP":   "DPoP",
+		"":       "",
+	} {
+		assert.Equal(t, want, normalizeTokenType(in), in)
+	}
+}
+
+func TestTokenResponse_Scopes(t *testing.T) {
+	for scope, want := range map[string][]string{
+		"read write":          {"read", "write"},
+		"read,write":          {"read", "write"},
+		"read, write  admin ": {"read", "write", "admin"},
+		"read read,write":     {"read", "write"},
+		"":                    nil,
+		" , ":                 nil,
+	} {
+		assert.Equal(t, want, TokenResponse{Scope: scope}.Scopes(), scope)
+	}
+}
+
+func TestTokenResponse_MissingScopes(t *testing.T) {
+	for _, tc := range []struct {
+		scope     string
+		requested []string
+		want      []string
+	}{
+		{scope: "a b c", requested: []string{"a", "b", "c"}},
+		{scope: "a b", requested: []string{"a", "b", "c"}, want: []string{"c"}},
+		{scope: "b", requested: []string{"c", "a", "b"}, want: []string{"c", "a"}},
+		{scope: "a,b,extra", requested: []string{"a", "b"}},
+		{scope: "", requested: []string{"a", "b"}},
+		{scope: "a", requested: nil},
+	} {
+		assert.Equal(t, tc.want, TokenResponse{Scope: tc.scope}.MissingScopes(tc.requested...), tc.scope)
+	}
+}
+
+func TestTokenResponse_ExpiresInDuration(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	resp := TokenResponse{ExpiresIn: 3600}
+	assert.Equal(t, time.Hour, resp.ExpiresInDuration())
+	assert.Equal(t, now.Add(time.Hour), resp.ExpiryTime(now))
+
+	for _, expiresIn := range []int{0, -1} {
+		resp := TokenResponse{ExpiresIn: expiresIn}
+		assert.Zero(t, resp.ExpiresInDuration())
+		assert.True(t, resp.ExpiryTime(now).IsZero())
+	}
+}
+
+func TestTokenTypeValidation(t *testing.T) {
+	ctx := context.Background()
+	tokenType := "DPoP"
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, err := rw.Write([]byte(`{"access_token":"token","token_type":"` + tokenType + `"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}))
+	require.NoError(t, err)
+
+	_, err = NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(ctx, "id", "secret")
+	require.EqualError(t, err, "failed to make create client credential token request: token endpoint returned a token that is not a bearer token")
+	safe, _ := werror.ParamsFromError(err)
+	assert.Equal(t, "DPoP", safe["tokenType"])
+
+	resp, err := NewClientCredentialClient(tokenHTTPClient, WithAllowAnyTokenType()).CreateClientCredentialTokenResponse(ctx, "id", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, &TokenResponse{AccessToken: "token", TokenType: "DPoP"}, resp)
+
+	for _, tokenType = range []string{"bearer", ""} {
+		token, err := NewClientCredentialClient(tokenHTTPClient).CreateClientCredentialToken(ctx, "id", "secret")
+		require.NoError(t, err, tokenType)
+		assert.Equal(t, "token", token)
+	}
+}
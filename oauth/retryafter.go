@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// retryAfterParam is the safe parameter of token endpoint errors that holds the delay requested by the server.
+const retryAfterParam = "retryAfter"
+
+// RetryAfter returns the delay that the token endpoint requested, using the Retry-After header, before the request that
+// failed with err is retried. It returns false if err was not returned for a response with a valid Retry-After header.
+func RetryAfter(err error) (time.Duration, bool) {
+	value, _ := werror.ParamFromError(err, retryAfterParam)
+	d, ok := value.(time.Duration)
+	return d, ok
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a number of seconds or an HTTP date, into a delay
+// relative to now. Dates in the past result in a delay of 0, and a number of seconds too large to be represented as a
+// time.Duration is invalid.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-10.2.3
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 || int64(seconds) > math.MaxInt64/int64(time.Second) {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := date.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
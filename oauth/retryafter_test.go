@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfter(t *testing.T) {
+	ctx := context.Background()
+	var retryAfter string
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if retryAfter != "" {
+			rw.Header().Set("Retry-After", retryAfter)
+		}
+		rw.WriteHeader(http.StatusTooManyRequests)
+		_, err := rw.Write([]byte(`{"error":"slow_down"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}), httpclient.WithMaxRetries(0))
+	require.NoError(t, err)
+	tokenClient := NewClientCredentialClient(tokenHTTPClient)
+
+	retryAfter = "30"
+	_, err = tokenClient.CreateClientCredentialToken(ctx, "id", "secret")
+	require.EqualError(t, err, "failed to make create client credential token request: httpclient request failed: 429 Too Many Requests")
+	d, ok := RetryAfter(err)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	retryAfter = ""
+	_, err = tokenClient.CreateClientCredentialToken(ctx, "id", "secret")
+	require.Error(t, err)
+	_, ok = RetryAfter(err)
+	assert.False(t, ok)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tc := range []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "seconds", value: "120", want: 2 * time.Minute, ok: true},
+		{name: "date", value: "Fri, 02 Jan 2026 03:05:05 GMT", want: time.Minute, ok: true},
+		{name: "date in the past", value: "Fri, 02 Jan 2026 03:03:05 GMT", want: 0, ok: true},
+		{name: "empty", value: "", ok: false},
+		{name: "negative", value: "-1", ok: false},
+		{name: "overflow", value: "9223372036854775807", ok: false},
+		{name: "invalid", value: "soon", ok: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ok := parseRetryAfter(tc.value, now)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, d)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/pkg/tlsconfig"
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// transportConfig configures the httpclient.Client built by the URL-based constructors. It is ignored by constructors
+// that accept an httpclient.Client, since the transport of a provided client cannot be changed.
+type transportConfig struct {
+	certificate *tls.Certificate
+	rootCAs     *x509.CertPool
+	params      []httpclient.ClientParam
+}
+
+// NewClientCredentialClientWithURLs returns a ClientCredentialClient that sends token requests to the provided base
+// URLs using an httpclient.Client built by this package. Because the returned client owns its transport, options such
+// as WithClientCertificate apply only to it, which allows one process to use a different client certificate for each
+// token endpoint. Requests fail over across baseURLs as described for NewClientCredentialClient.
+func NewClientCredentialClientWithURLs(baseURLs []string, opts ...ClientOption) (ClientCredentialClient, error) {
+	s := newServiceClient(nil, clientCredentialsEndpoint, opts)
+	params, err := s.transport.clientParams(baseURLs)
+	if err != nil {
+		return nil, err
+	}
+	client, err := httpclient.NewClient(params...)
+	if err != nil {
+		return nil, werror.Wrap(err, "failed to create token endpoint client")
+	}
+	s.client = client
+	return s, nil
+}
+
+// WithClientCertificate configures the client returned by NewClientCredentialClientWithURLs to present cert when the
+// token endpoint requests a TLS client certificate, as required by the "tls_client_auth" and
+// "self_signed_tls_client_auth" client authentication methods.
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(s *serviceClient) {
+		s.transport.certificate = &cert
+	}
+}
+
+// WithTLSClientAuth configures the client returned by NewClientCredentialClientWithURLs to authenticate using mutual
+// TLS with the CA-issued certificate cert, as required by the "tls_client_auth" method of RFC 8705. The client secret
+// is not sent. It is shorthand for WithClientCertificate(cert) and WithClientAuthMethod(ClientAuthMethodTLSClientAuth).
+//
+// Clients that provide their own httpclient.Client can instead configure the certificate on its TLS transport and use
+// WithClientAuthMethod(ClientAuthMethodTLSClientAuth).
+func WithTLSClientAuth(cert tls.Certificate) ClientOption {
+	return func(s *serviceClient) {
+		WithClientCertificate(cert)(s)
+		WithClientAuthMethod(ClientAuthMethodTLSClientAuth)(s)
+	}
+}
+
+// WithSelfSignedTLSClientAuth is like WithTLSClientAuth, but for the "self_signed_tls_client_auth" method of RFC 8705,
+// in which the token endpoint matches cert against the certificates registered for the client.
+func WithSelfSignedTLSClientAuth(cert tls.Certificate) ClientOption {
+	return func(s *serviceClient) {
+		WithClientCertificate(cert)(s)
+		WithClientAuthMethod(ClientAuthMethodSelfSignedTLSClientAuth)(s)
+	}
+}
+
+// WithRootCAs configures the client returned by NewClientCredentialClientWithURLs to verify the token endpoint's
+// certificate using pool instead of the system certificate pool.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(s *serviceClient) {
+		s.transport.rootCAs = pool
+	}
+}
+
+// WithHTTPClientParams appends params to those used to build the client returned by NewClientCredentialClientWithURLs.
+// They are applied after the parameters derived from other options, so an httpclient.WithTLSConfig provided here
+// replaces the configuration set by WithClientCertificate and WithRootCAs.
+func WithHTTPClientParams(params ...httpclient.ClientParam) ClientOption {
+	return func(s *serviceClient) {
+		s.transport.params = append(s.transport.params, params...)
+	}
+}
+
+// WithDisableHTTP2 configures the client returned by NewClientCredentialClientWithURLs to use HTTP/1.1 only. By default,
+// HTTP/2 is negotiated with token endpoints that support it, which lets concurrent token requests share a connection.
+func WithDisableHTTP2() ClientOption {
+	return WithHTTPClientParams(httpclient.WithDisableHTTP2())
+}
+
+// WithHTTP2HealthCheck configures how the client returned by NewClientCredentialClientWithURLs detects broken HTTP/2
+// connections: a connection that has not received a frame for readIdleTimeout is pinged, and closed if no response is
+// received within pingTimeout. Defaults to 30s and 15s respectively.
+func WithHTTP2HealthCheck(readIdleTimeout, pingTimeout time.Duration) ClientOption {
+	return WithHTTPClientParams(
+		httpclient.WithHTTP2ReadIdleTimeout(readIdleTimeout),
+		httpclient.WithHTTP2PingTimeout(pingTimeout),
+	)
+}
+
+// WithKeepAlive sets the TCP keep-alive period of connections made by the client returned by
+// NewClientCredentialClientWithURLs. Defaults to 30s.
+func WithKeepAlive(keepAlive time.Duration) ClientOption {
+	return WithHTTPClientParams(httpclient.WithKeepAlive(keepAlive))
+}
+
+// WithIdleConns configures the idle connections kept open for reuse by the client returned by
+// NewClientCredentialClientWithURLs. Services that refresh many tokens can raise maxIdleConnsPerHost to avoid
+// re-establishing connections to the token endpoint. Defaults to 200, 100 and 90s respectively.
+func WithIdleConns(maxIdleConns, maxIdleConnsPerHost int, idleConnTimeout time.Duration) ClientOption {
+	return WithHTTPClientParams(
+		httpclient.WithMaxIdleConns(maxIdleConns),
+		httpclient.WithMaxIdleConnsPerHost(maxIdleConnsPerHost),
+		httpclient.WithIdleConnTimeout(idleConnTimeout),
+	)
+}
+
+func (t transportConfig) clientParams(baseURLs []string) ([]httpclient.ClientParam, error) {
+	params := []httpclient.ClientParam{httpclient.WithBaseURLs(baseURLs)}
+	var tlsParams []tlsconfig.ClientParam
+	if t.certificate != nil {
+		cert := *t.certificate
+		tlsParams = append(tlsParams, tlsconfig.ClientKeyPair(func() (tls.Certificate, error) {
+			return cert, nil
+		}))
+	}
+	if t.rootCAs != nil {
+		pool := t.rootCAs
+		tlsParams = append(tlsParams, tlsconfig.ClientRootCAs(func() (*x509.CertPool, error) {
+			return pool, nil
+		}))
+	}
+	if len(tlsParams) > 0 {
+		tlsConfig, err := tlsconfig.NewClientConfig(tlsParams...)
+		if err != nil {
+			return nil, werror.Wrap(err, "failed to create token endpoint TLS configuration")
+		}
+		params = append(params, httpclient.WithTLSConfig(tlsConfig))
+	}
+	return append(params, t.params...), nil
+}
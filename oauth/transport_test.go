@@ -0,0 +1,193 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithClientCertificate(t *testing.T) {
+	ctx := context.Background()
+	newTokenServer := func(commonName string) *httptest.Server {
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if len(req.TLS.PeerCertificates) == 0 || req.TLS.PeerCertificates[0].Subject.CommonName != commonName {
+				rw.WriteHeader(http.StatusUnauthorized)
+				_, err := rw.Write([]byte(`{"error":"invalid_client"}`))
+				assert.NoError(t, err)
+				return
+			}
+			_, err := rw.Write([]byte(`{"access_token":"token-` + commonName + `"}`))
+			assert.NoError(t, err)
+		}))
+		srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+		srv.StartTLS()
+		return srv
+	}
+	srvA := newTokenServer("client-a")
+	defer srvA.Close()
+	srvB := newTokenServer("client-b")
+	defer srvB.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(srvA.Certificate())
+	rootCAs.AddCert(srvB.Certificate())
+
+	clientA, err := NewClientCredentialClientWithURLs([]string{srvA.URL},
+		WithClientCertificate(newTestCertificate(t, "client-a")),
+		WithRootCAs(rootCAs),
+	)
+	require.NoError(t, err)
+	clientB, err := NewClientCredentialClientWithURLs([]string{srvB.URL},
+		WithClientCertificate(newTestCertificate(t, "client-b")),
+		WithRootCAs(rootCAs),
+	)
+	require.NoError(t, err)
+
+	token, err := clientA.CreateClientCredentialToken(ctx, "id", "")
+	require.NoError(t, err)
+	assert.Equal(t, "token-client-a", token)
+	token, err = clientB.CreateClientCredentialToken(ctx, "id", "")
+	require.NoError(t, err)
+	assert.Equal(t, "token-client-b", token)
+
+	t.Run("no certificate", func(t *testing.T) {
+		client, err := NewClientCredentialClientWithURLs([]string{srvA.URL}, WithRootCAs(rootCAs))
+		require.NoError(t, err)
+		_, err = client.CreateClientCredentialToken(ctx, "id", "")
+		require.EqualError(t, err, "failed to make create client credential token request: httpclient request failed: 401 Unauthorized")
+	})
+}
+
+func TestWithTLSClientAuth(t *testing.T) {
+	ctx := context.Background()
+	tokenSrv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.NoError(t, req.ParseForm())
+		assert.Equal(t, url.Values{"grant_type": {"client_credentials"}, "client_id": {"client-a"}}, req.PostForm)
+		_, err := rw.Write([]byte(`{"access_token":"token-` + req.TLS.PeerCertificates[0].Subject.CommonName + `"}`))
+		assert.NoError(t, err)
+	}))
+	tokenSrv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	tokenSrv.StartTLS()
+	defer tokenSrv.Close()
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(tokenSrv.Certificate())
+
+	for _, opt := range []ClientOption{
+		WithTLSClientAuth(newTestCertificate(t, "client-a")),
+		WithSelfSignedTLSClientAuth(newTestCertificate(t, "client-a")),
+	} {
+		client, err := NewClientCredentialClientWithURLs([]string{tokenSrv.URL}, opt, WithRootCAs(rootCAs))
+		require.NoError(t, err)
+		token, err := client.CreateClientCredentialToken(ctx, "client-a", "unused-secret")
+		require.NoError(t, err)
+		assert.Equal(t, "token-client-a", token)
+	}
+
+	client, err := NewClientCredentialClientWithURLs([]string{tokenSrv.URL},
+		WithClientAuthMethod(ClientAuthMethodTLSClientAuth),
+		WithRootCAs(rootCAs),
+		WithHTTPClientParams(httpclient.WithMaxRetries(0)),
+	)
+	require.NoError(t, err)
+	_, err = client.CreateClientCredentialToken(ctx, "client-a", "unused-secret")
+	require.Error(t, err, "the server requires a client certificate")
+}
+
+func TestNewClientCredentialClientWithURLs_Failover(t *testing.T) {
+	ctx := context.Background()
+	downSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Fail(t, "request sent to stopped server")
+	}))
+	downURL := downSrv.URL
+	downSrv.Close()
+	var calls int
+	upSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer upSrv.Close()
+
+	client, err := NewClientCredentialClientWithURLs([]string{downURL, upSrv.URL},
+		WithHTTPClientParams(httpclient.WithInitialBackoff(time.Millisecond), httpclient.WithMaxBackoff(time.Millisecond)))
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		token, err := client.CreateClientCredentialToken(ctx, "id", "secret")
+		require.NoError(t, err)
+		assert.Equal(t, "token", token)
+	}
+	assert.Equal(t, 5, calls)
+}
+
+func TestWithDisableHTTP2(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, err := rw.Write([]byte(`{"access_token":"` + req.Proto + `"}`))
+		assert.NoError(t, err)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(srv.Certificate())
+
+	client, err := NewClientCredentialClientWithURLs([]string{srv.URL},
+		WithRootCAs(rootCAs),
+		WithKeepAlive(time.Minute),
+		WithIdleConns(10, 10, time.Minute),
+		WithHTTP2HealthCheck(time.Minute, time.Second),
+	)
+	require.NoError(t, err)
+	proto, err := client.CreateClientCredentialToken(ctx, "id", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", proto)
+
+	client, err = NewClientCredentialClientWithURLs([]string{srv.URL}, WithRootCAs(rootCAs), WithDisableHTTP2())
+	require.NoError(t, err)
+	proto, err = client.CreateClientCredentialToken(ctx, "id", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1", proto)
+}
+
+func newTestCertificate(t *testing.T, commonName string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
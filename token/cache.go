@@ -0,0 +1,131 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// CachedToken is a token persisted by a TokenCache.
+type CachedToken struct {
+	AccessToken string `json:"access_token"`
+	// RefreshToken is empty if no refresh token was issued.
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// Expiry is the time at which AccessToken expires, or the zero time if it is unknown.
+	Expiry time.Time `json:"expiry"`
+}
+
+// Expired returns whether the access token has expired as of now. A token with an unknown expiry is never expired.
+func (t CachedToken) Expired(now time.Time) bool {
+	return !t.Expiry.IsZero() && !now.Before(t.Expiry)
+}
+
+// TokenCache persists tokens between processes, such as invocations of a CLI. Keys identify the grant a token was
+// obtained with, for example the client ID and issuer, and can be computed using CacheKey.
+type TokenCache interface {
+	// Load returns the token stored under key. ok is false if no token is stored under key.
+	Load(ctx context.Context, key string) (token CachedToken, ok bool, err error)
+	// Store stores token under key, replacing any token already stored under it.
+	Store(ctx context.Context, key string, token CachedToken) error
+	// Delete removes the token stored under key. Deleting a key under which no token is stored is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// DefaultTokenCacheDir returns the directory in the user's configuration directory in which NewFileTokenCache
+// conventionally stores tokens.
+func DefaultTokenCacheDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", werror.Wrap(err, "failed to determine user configuration directory")
+	}
+	return filepath.Join(configDir, "go-oauth2-client", "tokens"), nil
+}
+
+type fileTokenCache struct {
+	dir string
+}
+
+// NewFileTokenCache returns a TokenCache that stores each token as a file in dir, which is created with mode 0700 if
+// it does not exist. Files are created with mode 0600 and are named by a hash of their key, so keys may contain any
+// characters. Stores replace files atomically, so concurrent processes never observe a partially written token.
+func NewFileTokenCache(dir string) TokenCache {
+	return &fileTokenCache{dir: dir}
+}
+
+func (c *fileTokenCache) Load(_ context.Context, key string) (CachedToken, bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return CachedToken{}, false, nil
+	}
+	if err != nil {
+		return CachedToken{}, false, werror.Wrap(err, "failed to read cached token")
+	}
+	var token CachedToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return CachedToken{}, false, werror.Wrap(err, "failed to unmarshal cached token")
+	}
+	return token, true, nil
+}
+
+func (c *fileTokenCache) Store(_ context.Context, key string, token CachedToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return werror.Wrap(err, "failed to marshal token")
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return werror.Wrap(err, "failed to create token cache directory")
+	}
+	// os.CreateTemp creates files with mode 0600
+	f, err := os.CreateTemp(c.dir, ".token-*")
+	if err != nil {
+		return werror.Wrap(err, "failed to create token cache file")
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return werror.Wrap(err, "failed to write token cache file")
+	}
+	if err := f.Close(); err != nil {
+		return werror.Wrap(err, "failed to write token cache file")
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil {
+		return werror.Wrap(err, "failed to replace token cache file")
+	}
+	return nil
+}
+
+func (c *fileTokenCache) Delete(_ context.Context, key string) error {
+	if err := os.Remove(c.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return werror.Wrap(err, "failed to delete cached token")
+	}
+	return nil
+}
+
+func (c *fileTokenCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
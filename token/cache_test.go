@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/palantir/go-oauth2-client/v2/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTokenCache(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "tokens")
+	cache := token.NewFileTokenCache(dir)
+	key := token.CacheKey("cli", nil, "https://idp")
+
+	_, ok, err := cache.Load(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	stored := token.CachedToken{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		Expiry:       time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, cache.Store(ctx, key, stored))
+	loaded, ok, err := cache.Load(ctx, key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, stored, loaded)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	dirInfo, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), dirInfo.Mode().Perm())
+
+	require.NoError(t, cache.Delete(ctx, key))
+	require.NoError(t, cache.Delete(ctx, key))
+	_, ok, err = cache.Load(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCachedToken_Expired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, token.CachedToken{}.Expired(now))
+	assert.False(t, token.CachedToken{Expiry: now.Add(time.Minute)}.Expired(now))
+	assert.True(t, token.CachedToken{Expiry: now}.Expired(now))
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+)
+
+// CacheKey returns a deterministic key identifying the token granted to clientID for the provided scopes and
+// audience. Scopes are treated as a set, so their order and any duplicates do not affect the key. The key is the
+// hex-encoded SHA-256 hash of a length-prefixed encoding of its inputs, so it does not reveal them and distinct inputs
+// cannot produce the same encoding.
+func CacheKey(clientID string, scopes []string, audience string) string {
+	sorted := append([]string(nil), scopes...)
+	sort.Strings(sorted)
+	h := sha256.New()
+	writeField := func(s string) {
+		_, _ = h.Write([]byte(strconv.Itoa(len(s)) + ":" + s))
+	}
+	writeField(clientID)
+	writeField(audience)
+	for i, scope := range sorted {
+		if i > 0 && scope == sorted[i-1] {
+			continue
+		}
+		writeField(scope)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
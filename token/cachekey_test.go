@@ -0,0 +1,40 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-oauth2-client/v2/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheKey(t *testing.T) {
+	key := token.CacheKey("client", []string{"read", "write"}, "api")
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, token.CacheKey("client", []string{"write", "read"}, "api"))
+	assert.Equal(t, key, token.CacheKey("client", []string{"write", "read", "write"}, "api"))
+
+	for name, other := range map[string]string{
+		"client":   token.CacheKey("other", []string{"read", "write"}, "api"),
+		"audience": token.CacheKey("client", []string{"read", "write"}, "other"),
+		"scopes":   token.CacheKey("client", []string{"read"}, "api"),
+		"boundary": token.CacheKey("client", []string{"readwrite"}, "api"),
+		"shifted":  token.CacheKey("clientapi", []string{"read", "write"}, ""),
+	} {
+		assert.NotEqual(t, key, other, name)
+	}
+	assert.Equal(t, token.CacheKey("client", nil, ""), token.CacheKey("client", []string{}, ""))
+}
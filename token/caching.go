@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewCachingProvider returns a Provider that caches the token returned by delegate for ttl. The first call, and the
+// first call after the cached token has become older than ttl, obtain a new token from delegate inline; no background
+// goroutine is started, unlike with a Refresher. Concurrent calls that find no usable token wait for a single call to
+// delegate. Errors are returned to the caller and are not cached, so the next call tries again.
+func NewCachingProvider(delegate Provider, ttl time.Duration) Provider {
+	var (
+		mu           sync.Mutex
+		token        string
+		acquiredTime time.Time
+	)
+	return func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if token != "" && time.Since(acquiredTime) < ttl {
+			return token, nil
+		}
+		newToken, err := delegate(ctx)
+		if err != nil {
+			return "", err
+		}
+		token, acquiredTime = newToken, time.Now()
+		return token, nil
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/go-oauth2-client/v2/token"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCachingProvider(t *testing.T) {
+	ctx := context.Background()
+	var calls int32
+	var fail atomic.Bool
+	provider := token.NewCachingProvider(func(_ context.Context) (string, error) {
+		if fail.Load() {
+			return "", werror.Error("failure")
+		}
+		n := atomic.AddInt32(&calls, 1)
+		time.Sleep(5 * time.Millisecond)
+		return "token-" + strconv.Itoa(int(n)), nil
+	}, 50*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tok, err := provider(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, "token-1", tok)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	time.Sleep(60 * time.Millisecond)
+	fail.Store(true)
+	_, err := provider(ctx)
+	require.EqualError(t, err, "failure")
+
+	fail.Store(false)
+	tok, err := provider(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", tok)
+}
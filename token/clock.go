@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"time"
+)
+
+// Clock is the source of time used by a Refresher to determine whether its token has expired and when Run refreshes
+// it. Tests can provide a Clock that is advanced manually to exercise expiry and refreshes deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer returns a Timer that fires once d has elapsed.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-use timer created by a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It returns false if the timer has already fired or been stopped.
+	Stop() bool
+}
+
+// WithClock sets the Clock used by a Refresher. Defaults to the system clock. The backoff between failed attempts to
+// refresh the token is not affected by the clock, but the delay requested by a Retry-After header is.
+func WithClock(clock Clock) RefresherOption {
+	return func(r *Refresher) {
+		r.clock = clock
+	}
+}
+
+// realClock is a Clock that uses the system clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{Timer: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
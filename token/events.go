@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+// subscriberBufferSize is the number of events buffered for each subscriber before the oldest events are dropped.
+const subscriberBufferSize = 8
+
+// TokenEvent describes the outcome of a single attempt by a Refresher to obtain a token from its Provider.
+type TokenEvent struct {
+	// Token is the token returned by the Provider. It is only meaningful if Err is nil.
+	Token string
+	// Err is the error returned by the Provider or nil if the attempt succeeded.
+	Err error
+}
+
+// WithOnRefresh sets a function that is called with the outcome of every attempt the Refresher makes to obtain a token,
+// whether made by Run or by ForceRefresh, once the outcome has been stored. It is called synchronously without holding
+// any lock that blocks Token, but a slow function delays the next attempt, so it should return quickly. err is nil if
+// the attempt succeeded.
+func WithOnRefresh(onRefresh func(token string, err error)) RefresherOption {
+	return func(r *Refresher) {
+		r.onRefresh = onRefresh
+	}
+}
+
+// Subscribe returns a channel on which a TokenEvent is sent after every attempt the Refresher makes to obtain a token.
+//
+// The channel buffers a small number of events. Sending never blocks the Refresher: if a subscriber does not keep up
+// and the buffer is full, the oldest buffered event is dropped to make room for the newest one, so a slow subscriber
+// always observes the most recent outcome but may miss intermediate ones.
+//
+// Callers must call Unsubscribe once they stop reading from the channel so that it can be released.
+func (r *Refresher) Subscribe() <-chan TokenEvent {
+	r.subscribersLock.Lock()
+	defer r.subscribersLock.Unlock()
+	if r.subscribers == nil {
+		r.subscribers = make(map[<-chan TokenEvent]chan TokenEvent)
+	}
+	ch := make(chan TokenEvent, subscriberBufferSize)
+	r.subscribers[ch] = ch
+	return ch
+}
+
+// Unsubscribe stops sending events to a channel returned by Subscribe and closes it. Events that are already buffered
+// remain readable. Unsubscribing a channel that is not subscribed is a no-op.
+func (r *Refresher) Unsubscribe(ch <-chan TokenEvent) {
+	r.subscribersLock.Lock()
+	defer r.subscribersLock.Unlock()
+	if sendCh, ok := r.subscribers[ch]; ok {
+		delete(r.subscribers, ch)
+		close(sendCh)
+	}
+}
+
+func (r *Refresher) publish(event TokenEvent) {
+	r.subscribersLock.Lock()
+	defer r.subscribersLock.Unlock()
+	for _, ch := range r.subscribers {
+		select {
+		case ch <- event:
+			continue
+		default:
+		}
+		// buffer is full: drop the oldest event and retry. The subscribers lock is held, so no other sender can
+		// refill the buffer in between.
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/palantir/witchcraft-go-logging/wlog"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+// WithStderrLoggerOverride configures the Refresher to write its logs to stderr, replacing the svc1log.Logger stored
+// on the context provided to Run even if one is set. This is intended for command-line tools that do not configure
+// svc1log, where a context without a logger would otherwise leave refresh failures invisible. Services that configure
+// svc1log should not use it, since their refresh logs would no longer be structured. Debug messages are not written.
+func WithStderrLoggerOverride() RefresherOption {
+	return func(r *Refresher) {
+		r.logger = newWriterLogger(os.Stderr, wlog.InfoLevel)
+	}
+}
+
+// writerLogger is a minimal svc1log.Logger that writes human-readable lines to an io.Writer. Unlike svc1log.New, it
+// does not depend on a wlog.LoggerProvider being registered. Unsafe parameters are never written.
+type writerLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level wlog.LogLevel
+}
+
+func newWriterLogger(w io.Writer, level wlog.LogLevel) *writerLogger {
+	return &writerLogger{
+		w:     w,
+		level: level,
+	}
+}
+
+func (l *writerLogger) Debug(msg string, params ...svc1log.Param) {
+	l.log(wlog.DebugLevel, msg, params)
+}
+
+func (l *writerLogger) Info(msg string, params ...svc1log.Param) {
+	l.log(wlog.InfoLevel, msg, params)
+}
+
+func (l *writerLogger) Warn(msg string, params ...svc1log.Param) {
+	l.log(wlog.WarnLevel, msg, params)
+}
+
+func (l *writerLogger) Error(msg string, params ...svc1log.Param) {
+	l.log(wlog.ErrorLevel, msg, params)
+}
+
+func (l *writerLogger) SetLevel(level wlog.LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+func (l *writerLogger) log(level wlog.LogLevel, msg string, params []svc1log.Param) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.level.Enabled(level) {
+		return
+	}
+	entry := wlog.NewMapLogEntry()
+	for _, p := range params {
+		svc1log.ApplyParam(p, entry)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(time.Now().Format(time.RFC3339))
+	sb.WriteString(" ")
+	sb.WriteString(strings.ToUpper(string(level)))
+	sb.WriteString(" ")
+	sb.WriteString(msg)
+	safeParams := entry.AnyMapValues()[svc1log.ParamsKey]
+	keys := make([]string, 0, len(safeParams))
+	for k := range safeParams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(&sb, " %s=%v", k, safeParams[k])
+	}
+	if stacktrace, ok := entry.StringValues()[svc1log.StacktraceKey]; ok {
+		sb.WriteString("\n")
+		sb.WriteString(stacktrace)
+	}
+	_, _ = fmt.Fprintln(l.w, sb.String())
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-logging/wlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefresher_LogRefreshResult(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRefresher(nil, time.Minute, WithFailureLogInterval(time.Hour))
+	r.logger = newWriterLogger(&buf, wlog.InfoLevel)
+	ctx := context.Background()
+
+	var streak failureStreak
+	for i := 0; i < 5; i++ {
+		r.logRefreshResult(ctx, &streak, werror.Error("failure"))
+	}
+	r.logRefreshResult(ctx, &streak, nil)
+	r.logRefreshResult(ctx, &streak, werror.Error("failure"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var messages []string
+	for _, line := range lines {
+		if strings.Contains(line, "ERROR") || strings.Contains(line, "INFO") {
+			messages = append(messages, line[strings.Index(line, " ")+1:])
+		}
+	}
+	assert.Equal(t, []string{
+		"ERROR Failed to refresh token, retrying. failedAttempts=1",
+		"INFO Refreshed token after previous attempts failed. failedAttempts=5",
+		"ERROR Failed to refresh token, retrying. failedAttempts=1",
+	}, messages)
+}
+
+func TestRefresher_WithStaleTokenWarning(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRefresher(nil, time.Hour, WithStaleTokenWarning(10*time.Minute), WithFailureLogInterval(time.Hour))
+	r.logger = newWriterLogger(&buf, wlog.InfoLevel)
+	r.markInitialized()
+	ctx := context.Background()
+
+	r.tokenData = tokenData{token: "foo", tokenAcquiredTime: time.Now().Add(-40 * time.Minute), tokenAcquireError: werror.Error("failure")}
+	_, err := r.Token(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String(), "token does not expire within the threshold")
+
+	r.tokenData = tokenData{token: "foo", tokenAcquiredTime: time.Now().Add(-55 * time.Minute)}
+	_, err = r.Token(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String(), "last refresh succeeded")
+
+	r.tokenData.tokenAcquireError = werror.Error("failure")
+	for i := 0; i < 3; i++ {
+		tok, err := r.Token(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "foo", tok)
+	}
+	assert.Equal(t, 1, strings.Count(buf.String(), "WARN Serving a token that expires soon because attempts to refresh it have failed."))
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+)
+
+const (
+	// RefreshTimerName is the name of the timer that records the duration of the Provider calls made by a Refresher.
+	RefreshTimerName = "oauth2.refresher.refresh"
+	// RefreshResultMeterName is the name of the meter that records the outcome of the Provider calls made by a Refresher.
+	RefreshResultMeterName = "oauth2.refresher.refresh.result"
+)
+
+// WithMetrics configures a Refresher to record the duration of each call to its Provider, whether made by Run or by
+// ForceRefresh, in the RefreshTimerName timer and its outcome in the RefreshResultMeterName meter, which is tagged
+// with a result of "success" or "failure". Metrics are registered on the registry of the context of the call, as
+// returned by metrics.FromContext.
+func WithMetrics() RefresherOption {
+	return func(r *Refresher) {
+		r.metrics = true
+	}
+}
+
+// recordRefresh records the duration and outcome of a Provider call if metrics are enabled.
+func (r *Refresher) recordRefresh(ctx context.Context, start time.Time, err error) {
+	if !r.metrics {
+		return
+	}
+	registry := metrics.FromContext(ctx)
+	registry.Timer(RefreshTimerName).UpdateSince(start)
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	registry.Meter(RefreshResultMeterName, metrics.MustNewTag("result", result)).Mark(1)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// MultiRefresher refreshes the tokens for several keys, such as combinations of scopes and audiences. The token for
+// each key is refreshed by its own Refresher, which is scheduled independently of the others, so a key whose provider
+// keeps failing does not delay the refresh of any other key. At most maxConcurrency provider calls are in flight at
+// once, across all keys, and each token is served by Token.
+type MultiRefresher struct {
+	refreshers map[string]*Refresher
+	keys       []string
+	// sem limits the number of provider calls in flight across all keys.
+	sem chan struct{}
+}
+
+// NewMultiRefresher constructs a MultiRefresher that uses providers[key] to obtain the token for each key, all of
+// which share tokenTTL. If maxConcurrency is not positive, the tokens for all keys are fetched concurrently.
+// The provided options are applied to the Refresher used for each key.
+func NewMultiRefresher(providers map[string]Provider, tokenTTL time.Duration, maxConcurrency int, opts ...RefresherOption) *MultiRefresher {
+	if maxConcurrency <= 0 || maxConcurrency > len(providers) {
+		maxConcurrency = len(providers)
+	}
+	m := &MultiRefresher{
+		refreshers: make(map[string]*Refresher, len(providers)),
+		keys:       make([]string, 0, len(providers)),
+		sem:        make(chan struct{}, maxConcurrency),
+	}
+	for key, provideToken := range providers {
+		m.refreshers[key] = NewRefresher(m.limitConcurrency(provideToken), tokenTTL, opts...)
+		m.keys = append(m.keys, key)
+	}
+	sort.Strings(m.keys)
+	return m
+}
+
+// limitConcurrency returns a Provider that calls provideToken once fewer than maxConcurrency provider calls are in
+// flight.
+func (m *MultiRefresher) limitConcurrency(provideToken Provider) Provider {
+	return func(ctx context.Context) (string, error) {
+		select {
+		case m.sem <- struct{}{}:
+		case <-ctx.Done():
+			return "", werror.Wrap(ctx.Err(), "context done while waiting to call token provider")
+		}
+		defer func() { <-m.sem }()
+		return provideToken(ctx)
+	}
+}
+
+// Keys returns the sorted keys whose tokens are refreshed.
+func (m *MultiRefresher) Keys() []string {
+	return append([]string(nil), m.keys...)
+}
+
+// Refresher returns the Refresher for key, which can be used to Invalidate or Stop the refresh of its token, or false
+// if no provider was configured for key.
+func (m *MultiRefresher) Refresher(key string) (*Refresher, bool) {
+	r, ok := m.refreshers[key]
+	return r, ok
+}
+
+// Token returns the current token for key. It behaves like Refresher.Token and returns an error if no provider was
+// configured for key.
+func (m *MultiRefresher) Token(ctx context.Context, key string) (string, error) {
+	r, ok := m.refreshers[key]
+	if !ok {
+		return "", werror.Error("no token provider is configured for key", werror.SafeParam("key", key))
+	}
+	return r.Token(ctx)
+}
+
+// Provider returns a Provider that serves the token for key, or an error if no provider was configured for key.
+func (m *MultiRefresher) Provider(key string) Provider {
+	return func(ctx context.Context) (string, error) {
+		return m.Token(ctx, key)
+	}
+}
+
+// Run calls Run on the Refresher for every key and is a blocking call; this will return once the Refresher for every
+// key has returned, which happens when the context is cancelled.
+func (m *MultiRefresher) Run(ctx context.Context) {
+	if len(m.keys) == 0 {
+		<-ctx.Done()
+		return
+	}
+	var wg sync.WaitGroup
+	for _, key := range m.keys {
+		r := m.refreshers[key]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Run(ctx)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/go-oauth2-client/v2/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiRefresher(t *testing.T) {
+	var inflight, maxInflight int32
+	newProvider := func(tok string) token.Provider {
+		return func(_ context.Context) (string, error) {
+			n := atomic.AddInt32(&inflight, 1)
+			defer atomic.AddInt32(&inflight, -1)
+			for {
+				m := atomic.LoadInt32(&maxInflight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInflight, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return tok, nil
+		}
+	}
+	refresher := token.NewMultiRefresher(map[string]token.Provider{
+		"read":  newProvider("read-token"),
+		"write": newProvider("write-token"),
+		"admin": newProvider("admin-token"),
+	}, time.Hour, 2)
+	assert.Equal(t, []string{"admin", "read", "write"}, refresher.Keys())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go refresher.Run(ctx)
+
+	tok, err := refresher.Token(ctx, "read")
+	require.NoError(t, err)
+	assert.Equal(t, "read-token", tok)
+	tok, err = refresher.Provider("write")(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "write-token", tok)
+	tok, err = refresher.Token(ctx, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "admin-token", tok)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInflight), int32(2))
+
+	_, err = refresher.Token(ctx, "unknown")
+	require.EqualError(t, err, "no token provider is configured for key")
+}
+
+func TestMultiRefresherFailingKey(t *testing.T) {
+	var readCalls int32
+	refresher := token.NewMultiRefresher(map[string]token.Provider{
+		"broken": func(_ context.Context) (string, error) {
+			return "", errors.New("invalid_scope")
+		},
+		"read": func(_ context.Context) (string, error) {
+			atomic.AddInt32(&readCalls, 1)
+			return "read-token", nil
+		},
+	}, 20*time.Millisecond, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go refresher.Run(ctx)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&readCalls) >= 3
+	}, 5*time.Second, 10*time.Millisecond, "read token was not rotated while the broken key was failing")
+	tok, err := refresher.Token(ctx, "read")
+	require.NoError(t, err)
+	assert.Equal(t, "read-token", tok)
+	_, err = refresher.Token(ctx, "broken")
+	require.EqualError(t, err, "all attempts to retrieve a token have failed: invalid_scope")
+}
@@ -32,9 +32,38 @@ type Provider = httpclient.TokenProvider
 // CreateAndStartRefreshingOAuthProvider returns a Provider which caches and periodically refreshes a client token.
 // When it returns, we have not yet necessarily successfully fetched a valid token.
 func CreateAndStartRefreshingOAuthProvider(ctx context.Context, client oauth.ClientCredentialClient, clientID, clientSecret string, refreshInterval time.Duration) Provider {
-	refresher := NewRefresher(func(ctx context.Context) (string, error) {
+	return CreateAndStartRefreshingProvider(ctx, func(ctx context.Context) (string, error) {
 		return client.CreateClientCredentialToken(ctx, clientID, clientSecret)
 	}, refreshInterval)
+}
+
+// CreateAndStartRefreshingProvider returns a Provider which caches and periodically refreshes the token returned by
+// provideToken. This allows the same caching and refreshing behavior to front any grant, such as the refresh_token
+// or token-exchange grants. When it returns, we have not yet necessarily successfully fetched a valid token.
+func CreateAndStartRefreshingProvider(ctx context.Context, provideToken Provider, refreshInterval time.Duration, opts ...RefresherOption) Provider {
+	refresher := NewRefresher(provideToken, refreshInterval, opts...)
 	go refresher.Run(ctx)
 	return refresher.Token
 }
+
+// WithClientCredentialsAuth returns an httpclient.ClientParam that authenticates requests with a client token
+// obtained using client, clientID and clientSecret. The token is cached and refreshed by a Refresher that is started
+// when WithClientCredentialsAuth is called and that runs until ctx is done, so ctx should outlive the httpclient.Client
+// the param is applied to.
+func WithClientCredentialsAuth(ctx context.Context, client oauth.ClientCredentialClient, clientID, clientSecret string, tokenTTL time.Duration, opts ...RefresherOption) httpclient.ClientParam {
+	return httpclient.WithAuthTokenProvider(CreateAndStartRefreshingProvider(ctx, func(ctx context.Context) (string, error) {
+		return client.CreateClientCredentialToken(ctx, clientID, clientSecret)
+	}, tokenTTL, opts...))
+}
+
+// NewClientCredentialsExpiringProvider returns an ExpiringProvider that obtains client tokens using client and reports
+// the expires_in value of each token response as the token's lifetime, for use with NewExpiringRefresher.
+func NewClientCredentialsExpiringProvider(client oauth.ClientCredentialClient, clientID, clientSecret string) ExpiringProvider {
+	return func(ctx context.Context) (string, time.Duration, error) {
+		resp, err := client.CreateClientCredentialTokenResponse(ctx, clientID, clientSecret)
+		if err != nil {
+			return "", 0, err
+		}
+		return resp.AccessToken, resp.ExpiresInDuration(), nil
+	}
+}
@@ -16,6 +16,7 @@ package token
 
 import (
 	"context"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -26,13 +27,118 @@ import (
 
 // Refresher periodically updates its token via its Provider.
 // This type provides thread-safe access to an up-to-date token.
+//
+// The Provider is called with the context of the operation that requested the token. Refreshes performed by Run use
+// the context provided to Run, so its values, such as the logger, trace and safe parameters, apply to every scheduled
+// refresh for as long as Run runs, and cancelling it aborts an in-progress refresh. On-demand refreshes made by
+// ForceRefresh and TokenWithMinValidity instead use the context of their caller, so that the token request is
+// correlated with the caller's logs and traces and is bounded by its deadline. Token never calls the Provider, so its
+// context only bounds how long it waits for the first token.
 type Refresher struct {
-	provideToken Provider
+	provideToken ExpiringProvider
 	tokenData    tokenData
 	// tokenDataInitialized represents whether a token has ever been acquired, with or without error by being a closed channel.
 	tokenDataInitialized chan struct{}
-	tokenTTL             time.Duration
-	tokenDataLock        sync.RWMutex
+	initializedOnce      sync.Once
+	// stopped is closed once Run has returned.
+	stopped     chan struct{}
+	stoppedOnce sync.Once
+	// stop is closed by Stop to make Run return.
+	stop     chan struct{}
+	stopOnce sync.Once
+	// refreshNow wakes Run to refresh immediately rather than at its next scheduled refresh.
+	refreshNow chan struct{}
+	// tokenTTL is the TTL of tokens for which the Provider does not report a lifetime.
+	tokenTTL      time.Duration
+	tokenDataLock sync.RWMutex
+	// logger, if non-nil, is used instead of the svc1log.Logger stored on the context provided to Run.
+	logger svc1log.Logger
+	// failureLogInterval is the minimum time between logs of consecutive failed refresh attempts.
+	failureLogInterval time.Duration
+	// startupBackoff configures the retries of the first refresh cycle of Run, which lasts until a token is acquired.
+	startupBackoff []retry.Option
+	// onRefresh, if non-nil, is called with the outcome of every attempt to obtain a token.
+	onRefresh func(token string, err error)
+	// subscribers maps each channel returned by Subscribe to its sendable counterpart.
+	subscribers     map[<-chan TokenEvent]chan TokenEvent
+	subscribersLock sync.Mutex
+	// inflight is the provider call currently in progress, or nil if there is none.
+	inflight     *inflightCall
+	inflightLock sync.Mutex
+	// metrics enables recording the duration and outcome of Provider calls.
+	metrics bool
+	// clock determines token expiry and schedules the refreshes performed by Run.
+	clock Clock
+	// randomizationFactor is the fraction by which the interval between the refreshes performed by Run is varied.
+	randomizationFactor float64
+	// staleWarningThreshold, if positive, is the remaining validity below which serving a token after failed refreshes
+	// is logged as a warning.
+	staleWarningThreshold time.Duration
+	lastStaleWarning      time.Time
+	staleWarningLock      sync.Mutex
+}
+
+// inflightCall is a single call to a Refresher's Provider that concurrent callers can wait on.
+type inflightCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// ExpiringProvider returns a token along with its lifetime, such as the expires_in value of an OAuth2 token response.
+// A lifetime that is not positive means that the lifetime is unknown.
+type ExpiringProvider func(ctx context.Context) (token string, expiresIn time.Duration, err error)
+
+// RefresherOption configures optional behavior of a Refresher.
+type RefresherOption func(*Refresher)
+
+const defaultFailureLogInterval = time.Minute
+
+// WithFailureLogInterval sets the minimum time between error logs while consecutive refresh attempts made by Run keep
+// failing. The first failure of a streak is always logged. Defaults to one minute.
+func WithFailureLogInterval(interval time.Duration) RefresherOption {
+	return func(r *Refresher) {
+		r.failureLogInterval = interval
+	}
+}
+
+// WithStartupBackoff sets the initial and maximum backoff between the attempts Run makes to acquire its first token,
+// which continue until one succeeds or the context is cancelled. This determines how quickly a refresher recovers
+// when the token endpoint is briefly unavailable at startup, independent of the steady-state refresh interval.
+// Defaults to the backoff of retry.Do, which starts at 50ms and is capped at 2s.
+func WithStartupBackoff(initialBackoff, maxBackoff time.Duration) RefresherOption {
+	return func(r *Refresher) {
+		r.startupBackoff = []retry.Option{
+			retry.WithInitialBackoff(initialBackoff),
+			retry.WithMaxBackoff(maxBackoff),
+		}
+	}
+}
+
+// WithRefreshJitter sets the fraction by which the interval between the refreshes performed by Run is randomly varied:
+// each interval is chosen uniformly from [interval*(1-factor), interval*(1+factor)]. Large fleets can increase it to
+// spread refreshes against the token endpoint, and tests can set it to 0 for deterministic intervals. Defaults to 0.2.
+// A factor below 0, or NaN, is treated as 0, and a factor above 1 is treated as 1, so that intervals are never negative.
+func WithRefreshJitter(factor float64) RefresherOption {
+	switch {
+	case !(factor >= 0):
+		factor = 0
+	case factor > 1:
+		factor = 1
+	}
+	return func(r *Refresher) {
+		r.randomizationFactor = factor
+	}
+}
+
+// WithStaleTokenWarning configures Token to log a warning when it serves a token that expires within threshold while the
+// most recent attempt to refresh it has failed. This makes an outage of the token endpoint visible before the token
+// expires and requests start to fail. Warnings are logged at most once per failure log interval; see
+// WithFailureLogInterval. By default, no warning is logged.
+func WithStaleTokenWarning(threshold time.Duration) RefresherOption {
+	return func(r *Refresher) {
+		r.staleWarningThreshold = threshold
+	}
 }
 
 type tokenData struct {
@@ -40,13 +146,33 @@ type tokenData struct {
 	token string
 	// tokenAcquiredTime is the time token was acquired without error or nil if this has never happened
 	tokenAcquiredTime time.Time
+	// tokenTTL is the lifetime of token, or zero if token has never been acquired
+	tokenTTL time.Duration
 	// tokenAcquireError represents the error from the most recent token acquire attempt or nil if no attempt has been made
 	tokenAcquireError error
 }
 
+// ttl returns the lifetime of the stored token, or defaultTTL if no token has been acquired.
+func (d tokenData) ttl(defaultTTL time.Duration) time.Duration {
+	if d.tokenTTL > 0 {
+		return d.tokenTTL
+	}
+	return defaultTTL
+}
+
 // NewRefresher constructs a Refresher from a Provider and a token's TTL.
-func NewRefresher(provideToken Provider, tokenTTL time.Duration) *Refresher {
-	return &Refresher{
+func NewRefresher(provideToken Provider, tokenTTL time.Duration, opts ...RefresherOption) *Refresher {
+	return NewExpiringRefresher(func(ctx context.Context) (string, time.Duration, error) {
+		token, err := provideToken(ctx)
+		return token, 0, err
+	}, tokenTTL, opts...)
+}
+
+// NewExpiringRefresher constructs a Refresher from an ExpiringProvider. Each token is considered valid for the lifetime
+// reported with it, and Run refreshes it after half of that lifetime. defaultTokenTTL is used for tokens whose lifetime
+// is not reported.
+func NewExpiringRefresher(provideToken ExpiringProvider, defaultTokenTTL time.Duration, opts ...RefresherOption) *Refresher {
+	r := &Refresher{
 		provideToken: provideToken,
 		tokenData: tokenData{
 			token:             "",
@@ -54,15 +180,33 @@ func NewRefresher(provideToken Provider, tokenTTL time.Duration) *Refresher {
 			tokenAcquireError: werror.Error("token is not yet initialized"),
 		},
 		tokenDataInitialized: make(chan struct{}),
-		tokenTTL:             tokenTTL,
+		stopped:              make(chan struct{}),
+		stop:                 make(chan struct{}),
+		refreshNow:           make(chan struct{}, 1),
+		tokenTTL:             defaultTokenTTL,
+		failureLogInterval:   defaultFailureLogInterval,
+		clock:                realClock{},
+		randomizationFactor:  defaultRandomizationFactor,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 // Token returns the currently stored token or an error if (1) there is no token stored and an attempt to get the token has failed, or (2) the stored token is not usable.
-// This method will block until an attempt is completed to the provider to get the token (either success or fail).
+// This method will block until an attempt is completed to the provider to get the token (either success or fail), or
+// until Run returns without having completed such an attempt.
 func (r *Refresher) Token(ctx context.Context) (string, error) {
+	token, _, err := r.TokenWithExpiry(ctx)
+	return token, err
+}
+
+// TokenWithExpiry is like Token, but also returns the time at which the returned token expires, which is the time it
+// was acquired plus its TTL.
+func (r *Refresher) TokenWithExpiry(ctx context.Context) (string, time.Time, error) {
 	if err := r.waitForInitialized(ctx); err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 	r.tokenDataLock.RLock()
 	defer r.tokenDataLock.RUnlock()
@@ -74,21 +218,73 @@ func (r *Refresher) Token(ctx context.Context) (string, error) {
 	//     * the stored token is expired
 	//         * the last n attempts to get the token have all failed
 	//         * there have been no completed attempts since the last success
+	tokenTTL := r.tokenData.ttl(r.tokenTTL)
 	errorParam := werror.SafeParams(map[string]interface{}{
 		"tokenAcquiredTime": r.tokenData.tokenAcquiredTime,
-		"tokenTTL":          r.tokenTTL,
+		"tokenTTL":          tokenTTL,
 	})
 	if r.tokenData.token == "" {
-		return "", werror.Wrap(r.tokenData.tokenAcquireError, "all attempts to retrieve a token have failed", errorParam)
+		return "", time.Time{}, werror.Wrap(r.tokenData.tokenAcquireError, "all attempts to retrieve a token have failed", errorParam)
 	}
-	if time.Now().Sub(r.tokenData.tokenAcquiredTime) > r.tokenTTL {
+	if r.clock.Now().Sub(r.tokenData.tokenAcquiredTime) > tokenTTL {
 		if r.tokenData.tokenAcquireError != nil {
-			return "", werror.Wrap(r.tokenData.tokenAcquireError, "token is expired, attempts to obtain new token have failed", errorParam)
+			return "", time.Time{}, werror.Wrap(r.tokenData.tokenAcquireError, "token is expired, attempts to obtain new token have failed", errorParam)
 		}
-		return "", werror.Wrap(r.tokenData.tokenAcquireError, "token is expired, attempts to obtain new token have not completed", errorParam)
+		return "", time.Time{}, werror.Wrap(r.tokenData.tokenAcquireError, "token is expired, attempts to obtain new token have not completed", errorParam)
 	}
 	// otherwise we have a token that is usable, even if the last attempt to get a token failed
-	return r.tokenData.token, nil
+	expiry := r.tokenData.tokenAcquiredTime.Add(tokenTTL)
+	if r.tokenData.tokenAcquireError != nil {
+		r.warnIfStale(ctx, expiry, r.tokenData.tokenAcquireError)
+	}
+	return r.tokenData.token, expiry, nil
+}
+
+// warnIfStale logs a warning if a token that expires at expiry is within the stale warning threshold, since the most
+// recent attempt to refresh it failed with err.
+func (r *Refresher) warnIfStale(ctx context.Context, expiry time.Time, err error) {
+	if r.staleWarningThreshold <= 0 {
+		return
+	}
+	now := r.clock.Now()
+	remaining := expiry.Sub(now)
+	if remaining >= r.staleWarningThreshold {
+		return
+	}
+	r.staleWarningLock.Lock()
+	if !r.lastStaleWarning.IsZero() && now.Sub(r.lastStaleWarning) < r.failureLogInterval {
+		r.staleWarningLock.Unlock()
+		return
+	}
+	r.lastStaleWarning = now
+	r.staleWarningLock.Unlock()
+	r.loggerFromContext(ctx).Warn("Serving a token that expires soon because attempts to refresh it have failed.",
+		svc1log.SafeParam("remainingValidity", remaining.String()),
+		svc1log.Stacktrace(err))
+}
+
+// TokenWithMinValidity returns the current token if it remains valid for at least minValidity, based on the time it
+// was acquired and the token TTL. Otherwise, it obtains a new token using ForceRefresh and returns it. This allows
+// callers starting long-running operations to avoid a token that would expire partway through.
+// An error is returned if minValidity exceeds the token TTL, since no token could satisfy it.
+func (r *Refresher) TokenWithMinValidity(ctx context.Context, minValidity time.Duration) (string, error) {
+	r.tokenDataLock.RLock()
+	data := r.tokenData
+	r.tokenDataLock.RUnlock()
+	tokenTTL := data.ttl(r.tokenTTL)
+	if minValidity > tokenTTL {
+		return "", werror.Error("requested minimum validity exceeds token TTL",
+			werror.SafeParam("minValidity", minValidity.String()),
+			werror.SafeParam("tokenTTL", tokenTTL.String()))
+	}
+	if data.token != "" && data.tokenAcquiredTime.Add(tokenTTL).Sub(r.clock.Now()) >= minValidity {
+		return data.token, nil
+	}
+	token, err := r.ForceRefresh(ctx)
+	if err != nil {
+		return "", werror.Wrap(err, "failed to refresh token with insufficient remaining validity")
+	}
+	return token, nil
 }
 
 func (r *Refresher) waitForInitialized(ctx context.Context) error {
@@ -97,59 +293,237 @@ func (r *Refresher) waitForInitialized(ctx context.Context) error {
 		return werror.Wrap(ctx.Err(), "context completed while waiting for initialized")
 	case <-r.tokenDataInitialized:
 		return nil
+	case <-r.stopped:
+		// an attempt may have completed just before Run returned
+		select {
+		case <-r.tokenDataInitialized:
+			return nil
+		default:
+		}
+		return werror.Error("refresher stopped before acquiring token")
 	}
 }
 
-// TokenTTL returns the TTL of the token.
+// TokenTTL returns the TTL of the current token. This is the lifetime reported by the provider of a Refresher created
+// with NewExpiringRefresher, and the configured TTL if no lifetime was reported or no token has been acquired.
 func (r *Refresher) TokenTTL() time.Duration {
-	return r.tokenTTL
+	r.tokenDataLock.RLock()
+	defer r.tokenDataLock.RUnlock()
+	return r.tokenData.ttl(r.tokenTTL)
 }
 
-// Run starts an endless refresh loop and is a blocking call; this will return once the context is cancelled.
-func (r *Refresher) Run(ctx context.Context) {
+// EffectiveRefreshInterval returns the base interval between the refreshes performed by Run, before jitter is applied.
+// It is derived from the TTL of the current token, so it may change after every refresh.
+func (r *Refresher) EffectiveRefreshInterval() time.Duration {
 	// divide by two so we get a new token ahead of expiry
-	refreshInterval := r.tokenTTL / 2
-	fuzzyTicker := retry.Start(ctx,
-		retry.WithInitialBackoff(refreshInterval),
-		retry.WithMaxBackoff(refreshInterval),
-		retry.WithRandomizationFactor(0.2),
-	)
-
-	for fuzzyTicker.Next() {
-		_ = retry.Do(ctx, func() error {
-			svc1log.FromContext(ctx).Debug("Attempting to retrieve token from provider.")
-			token, err := r.provideToken(ctx)
-			if err != nil {
-				svc1log.FromContext(ctx).Error("Failed to refresh token, retrying.", svc1log.Stacktrace(err))
-			}
-			r.updateToken(token, err)
-			return err
-		})
-	}
-}
-
-func (r *Refresher) updateToken(token string, err error) {
+	return r.TokenTTL() / 2
+}
+
+// defaultRandomizationFactor is the default fraction by which the interval between the refreshes performed by Run is
+// randomly varied, so that refreshers started at the same time do not refresh in lockstep.
+const defaultRandomizationFactor = 0.2
+
+// Run starts an endless refresh loop and is a blocking call; this will return once the context is cancelled or Stop is
+// called. Once Run returns, calls to Token that are waiting for the first token are unblocked with an error, and the
+// channel returned by Done is closed.
+func (r *Refresher) Run(ctx context.Context) {
+	defer r.markStopped()
+	select {
+	case <-r.stop:
+		return
+	default:
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var streak failureStreak
+	retryOpts := r.startupBackoff
+	for ctx.Err() == nil {
+		r.refreshUntilSuccess(ctx, &streak, retryOpts...)
+		retryOpts = nil
+		timer := r.clock.NewTimer(randomize(r.EffectiveRefreshInterval(), r.randomizationFactor))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		case <-r.refreshNow:
+			timer.Stop()
+		}
+	}
+}
+
+// Invalidate discards the current token, for example because the server has revoked it. Until a new token is acquired,
+// Token returns an error. If Run is running, it obtains a new token immediately instead of at its next scheduled
+// refresh; otherwise, callers can obtain one using ForceRefresh.
+func (r *Refresher) Invalidate() {
+	r.tokenDataLock.Lock()
+	r.tokenData = tokenData{
+		tokenAcquireError: werror.Error("token was invalidated"),
+	}
+	r.tokenDataLock.Unlock()
+	select {
+	case r.refreshNow <- struct{}{}:
+	default:
+	}
+}
+
+// Stop makes Run return, or return immediately if it has not yet been called. It does not wait for Run to return; use
+// Done for that. It is safe to call Stop any number of times.
+func (r *Refresher) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Done returns a channel that is closed once Run has returned.
+func (r *Refresher) Done() <-chan struct{} {
+	return r.stopped
+}
+
+// randomize returns a random duration in [d*(1-factor), d*(1+factor)].
+func randomize(d time.Duration, factor float64) time.Duration {
+	return time.Duration(float64(d) * (1 + factor*(2*rand.Float64()-1)))
+}
+
+// refreshUntilSuccess fetches a token, retrying with backoff until an attempt succeeds or ctx is done. A retry is made
+// no sooner than the delay requested by a Retry-After header of the failed response, up to maxRetryAfter.
+func (r *Refresher) refreshUntilSuccess(ctx context.Context, streak *failureStreak, retryOpts ...retry.Option) {
+	_ = retry.Do(ctx, func() error {
+		r.loggerFromContext(ctx).Debug("Attempting to retrieve token from provider.")
+		_, err := r.fetchToken(ctx)
+		r.logRefreshResult(ctx, streak, err)
+		if err != nil {
+			waitRetryAfter(ctx, r.clock, err)
+		}
+		return err
+	}, retryOpts...)
+}
+
+// failureStreak tracks the consecutive failed refresh attempts made by Run.
+type failureStreak struct {
+	attempts   int
+	lastLogged time.Time
+}
+
+// logRefreshResult logs the outcome of a refresh attempt. A streak of failures is logged when it starts and then at
+// most once per failureLogInterval, and the first success after a streak is logged so that recovery is visible.
+func (r *Refresher) logRefreshResult(ctx context.Context, streak *failureStreak, err error) {
+	if err == nil {
+		if streak.attempts > 0 {
+			r.loggerFromContext(ctx).Info("Refreshed token after previous attempts failed.",
+				svc1log.SafeParam("failedAttempts", streak.attempts))
+		}
+		*streak = failureStreak{}
+		return
+	}
+	streak.attempts++
+	now := r.clock.Now()
+	if streak.attempts > 1 && now.Sub(streak.lastLogged) < r.failureLogInterval {
+		return
+	}
+	streak.lastLogged = now
+	r.loggerFromContext(ctx).Error("Failed to refresh token, retrying.",
+		svc1log.SafeParam("failedAttempts", streak.attempts),
+		svc1log.Stacktrace(err))
+}
+
+// ForceRefresh immediately obtains a new token from the Provider, independent of the refresh schedule of Run, and
+// stores the result as the current token. It returns the token or error returned by the Provider.
+//
+// At most one call to the Provider is in flight at a time: if a refresh is already in progress, whether started by
+// Run or by another call to ForceRefresh, this call waits for it and returns its result instead of calling the
+// Provider again.
+//
+// If this call starts a refresh, the Provider is called with ctx, so the values of ctx are propagated to the token
+// request and cancelling ctx aborts it, which also fails the callers waiting for it. If it joins a refresh in
+// progress, ctx only bounds how long it waits.
+func (r *Refresher) ForceRefresh(ctx context.Context) (string, error) {
+	return r.fetchToken(ctx)
+}
+
+// fetchToken calls the Provider and stores its result, coalescing concurrent calls onto a single in-flight call.
+func (r *Refresher) fetchToken(ctx context.Context) (string, error) {
+	r.inflightLock.Lock()
+	if call := r.inflight; call != nil {
+		r.inflightLock.Unlock()
+		select {
+		case <-ctx.Done():
+			return "", werror.Wrap(ctx.Err(), "context completed while waiting for in-flight token request")
+		case <-call.done:
+			return call.token, call.err
+		}
+	}
+	call := &inflightCall{done: make(chan struct{})}
+	r.inflight = call
+	r.inflightLock.Unlock()
+
+	var expiresIn time.Duration
+	start := time.Now()
+	call.token, expiresIn, call.err = r.provideToken(ctx)
+	r.recordRefresh(ctx, start, call.err)
+	r.updateToken(call.token, expiresIn, call.err)
+	if r.onRefresh != nil {
+		r.onRefresh(call.token, call.err)
+	}
+	r.publish(TokenEvent{Token: call.token, Err: call.err})
+
+	r.inflightLock.Lock()
+	r.inflight = nil
+	r.inflightLock.Unlock()
+	close(call.done)
+	return call.token, call.err
+}
+
+func (r *Refresher) loggerFromContext(ctx context.Context) svc1log.Logger {
+	if r.logger != nil {
+		return r.logger
+	}
+	return svc1log.FromContext(ctx)
+}
+
+func (r *Refresher) updateToken(token string, expiresIn time.Duration, err error) {
 	r.tokenDataLock.Lock()
 	defer r.tokenDataLock.Unlock()
 	var newTokenData tokenData
 	if err == nil {
+		if expiresIn <= 0 {
+			expiresIn = r.tokenTTL
+		}
 		newTokenData = tokenData{
 			token:             token,
-			tokenAcquiredTime: time.Now(),
+			tokenAcquiredTime: r.clock.Now(),
+			tokenTTL:          expiresIn,
 			tokenAcquireError: nil,
 		}
 	} else {
 		newTokenData = tokenData{
 			token:             r.tokenData.token,
 			tokenAcquiredTime: r.tokenData.tokenAcquiredTime,
+			tokenTTL:          r.tokenData.tokenTTL,
 			tokenAcquireError: err,
 		}
 	}
 	r.tokenData = newTokenData
-	// close channel if it is not already closed
-	select {
-	case <-r.tokenDataInitialized:
-	default:
+	r.markInitialized()
+}
+
+// markStopped records that Run has returned. It is safe to call any number of times.
+func (r *Refresher) markStopped() {
+	r.stoppedOnce.Do(func() {
+		close(r.stopped)
+	})
+}
+
+// markInitialized records that an attempt to acquire a token has completed. It is safe to call any number of times.
+func (r *Refresher) markInitialized() {
+	r.initializedOnce.Do(func() {
 		close(r.tokenDataInitialized)
-	}
+	})
 }
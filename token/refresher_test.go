@@ -16,11 +16,16 @@ package token_test
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/palantir/go-oauth2-client/v2/token"
+	"github.com/palantir/pkg/metrics"
 	"github.com/palantir/pkg/retry"
 	werror "github.com/palantir/witchcraft-go-error"
 	"github.com/stretchr/testify/assert"
@@ -68,21 +73,25 @@ func TestRefresher_Run(t *testing.T) {
 	wg.Wait()
 }
 
-// Note, this test asssumes a certain accuracy of time.Sleep that can't actually be guaranteed, while it's unlikely to
-// fail it does add a bit of fragility in order to preserve readability
 func TestRefresher_RunFailsAfterSucceeding(t *testing.T) {
-	shouldFail := false
-	hasFailed := false
+	var shouldFail atomic.Bool
+	failed := make(chan struct{}, 1)
 	provideToken := func(_ context.Context) (string, error) {
-		if shouldFail {
-			hasFailed = true
+		if shouldFail.Load() {
 			return "badtoken", werror.Error("failure")
-		} else {
-			return "goodtoken", nil
 		}
+		return "goodtoken", nil
 	}
-	ttl := time.Millisecond * 20
-	refresher := token.NewRefresher(provideToken, ttl)
+	ttl := time.Hour
+	clock := newFakeClock()
+	refresher := token.NewRefresher(provideToken, ttl, token.WithClock(clock), token.WithOnRefresh(func(_ string, err error) {
+		if err != nil {
+			select {
+			case failed <- struct{}{}:
+			default:
+			}
+		}
+	}))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -94,26 +103,26 @@ func TestRefresher_RunFailsAfterSucceeding(t *testing.T) {
 		refresher.Run(ctx)
 	}()
 
-	// Sleep up until before the refresh attempt, which occurs at 1/2 * ttl
-	time.Sleep(ttl / 4)
 	token, err := refresher.Token(context.Background())
 	assert.Equal(t, "goodtoken", token)
 	assert.NoError(t, err)
 
-	shouldFail = true
+	shouldFail.Store(true)
 
-	// Sleep past attempted refresh, which occurs at 1/2 * ttl, after this sleep we are at 3/4 * ttl, so the token is still valid even though a failure has occurred
-	time.Sleep(ttl / 2)
+	// Advance past the refresh, which is scheduled at 1/2 * ttl with jitter of at most 20%, to 3/4 * ttl, so the token
+	// is still valid even though a failure has occurred
+	clock.waitForTimer(t)
+	clock.Advance(ttl * 3 / 4)
+	<-failed
 	token, err = refresher.Token(context.Background())
 	assert.Equal(t, "goodtoken", token)
 	assert.NoError(t, err)
-	assert.True(t, hasFailed)
 
-	// Sleep past ttl
-	time.Sleep(ttl / 2)
+	// Advance past ttl
+	clock.Advance(ttl / 2)
 	token, err = refresher.Token(context.Background())
 	assert.Equal(t, "", token)
-	assert.Error(t, err)
+	assert.EqualError(t, err, "token is expired, attempts to obtain new token have failed: failure")
 
 	cancel()
 	wg.Wait()
@@ -206,3 +215,498 @@ func TestRefresher_ErrorsOnProviderError(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "foo")
 }
+
+func TestRefresher_Subscribe(t *testing.T) {
+	shouldFail := true
+	provideToken := func(_ context.Context) (string, error) {
+		if shouldFail {
+			shouldFail = false
+			return "", werror.Error("failure")
+		}
+		return "foo", nil
+	}
+
+	refresher := token.NewRefresher(provideToken, time.Second)
+	events := refresher.Subscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go refresher.Run(ctx)
+
+	event := <-events
+	assert.Equal(t, "", event.Token)
+	assert.EqualError(t, event.Err, "failure")
+
+	event = <-events
+	assert.Equal(t, "foo", event.Token)
+	assert.NoError(t, event.Err)
+
+	refresher.Unsubscribe(events)
+	_, ok := <-events
+	assert.False(t, ok, "expected channel to be closed after Unsubscribe")
+}
+
+func TestRefresher_ForceRefreshCoalescesConcurrentCalls(t *testing.T) {
+	const numCallers = 50
+	var calls, joined int32
+	provideToken := func(_ context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		waitForJoiners(t, &joined, numCallers-1)
+		return "foo", nil
+	}
+	refresher := token.NewRefresher(provideToken, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numCallers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := refresher.ForceRefresh(newJoinCountingContext(&joined))
+			assert.NoError(t, err)
+			assert.Equal(t, "foo", token)
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	token, err := refresher.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "foo", token)
+}
+
+func TestRefresher_TokenUnblocksWhenRunStopsBeforeAcquiringToken(t *testing.T) {
+	var calls int32
+	provideToken := func(_ context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "foo", nil
+	}
+	refresher := token.NewRefresher(provideToken, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	refresher.Run(ctx)
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), time.Second)
+	defer timeoutCancel()
+	_, err := refresher.Token(timeoutCtx)
+	require.EqualError(t, err, "refresher stopped before acquiring token")
+	assert.EqualValues(t, 0, atomic.LoadInt32(&calls))
+}
+
+func TestRefresher_EffectiveRefreshInterval(t *testing.T) {
+	refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+		return "foo", nil
+	}, time.Minute)
+	assert.Equal(t, 30*time.Second, refresher.EffectiveRefreshInterval())
+}
+
+func TestRefresher_TokenWithMinValidity(t *testing.T) {
+	var calls int32
+	provideToken := func(_ context.Context) (string, error) {
+		return "token-" + strconv.Itoa(int(atomic.AddInt32(&calls, 1))), nil
+	}
+	ttl := time.Minute
+	clock := newFakeClock()
+	refresher := token.NewRefresher(provideToken, ttl, token.WithClock(clock))
+	ctx := context.Background()
+
+	// no token has been acquired yet, so a refresh is forced
+	tok, err := refresher.TokenWithMinValidity(ctx, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", tok)
+
+	// the current token is valid for long enough
+	tok, err = refresher.TokenWithMinValidity(ctx, ttl/2)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", tok)
+
+	// the remaining validity of the current token equals the requested minimum
+	clock.Advance(time.Second)
+	tok, err = refresher.TokenWithMinValidity(ctx, ttl-time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", tok)
+
+	// the current token is no longer valid for the full TTL
+	tok, err = refresher.TokenWithMinValidity(ctx, ttl)
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", tok)
+
+	_, err = refresher.TokenWithMinValidity(ctx, 2*ttl)
+	require.EqualError(t, err, "requested minimum validity exceeds token TTL")
+}
+
+func TestRefresher_WithStartupBackoff(t *testing.T) {
+	var attempts int32
+	provideToken := func(_ context.Context) (string, error) {
+		if atomic.AddInt32(&attempts, 1) <= 5 {
+			return "", werror.Error("failure")
+		}
+		return "foo", nil
+	}
+	// with the default backoff, five failed attempts take over a second to retry
+	refresher := token.NewRefresher(provideToken, time.Hour, token.WithStartupBackoff(time.Millisecond, 5*time.Millisecond))
+	events := refresher.Subscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	go refresher.Run(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			require.Fail(t, "timed out waiting for first token")
+		case event := <-events:
+			if event.Err != nil {
+				continue
+			}
+			assert.Equal(t, "foo", event.Token)
+			assert.EqualValues(t, 6, atomic.LoadInt32(&attempts))
+			return
+		}
+	}
+}
+
+func TestExpiringRefresher(t *testing.T) {
+	var expiresIn atomic.Int64
+	var calls int32
+	refresher := token.NewExpiringRefresher(func(_ context.Context) (string, time.Duration, error) {
+		n := atomic.AddInt32(&calls, 1)
+		return "token-" + strconv.Itoa(int(n)), time.Duration(expiresIn.Load()), nil
+	}, time.Hour)
+	ctx := context.Background()
+	assert.Equal(t, time.Hour, refresher.TokenTTL())
+	assert.Equal(t, 30*time.Minute, refresher.EffectiveRefreshInterval())
+
+	expiresIn.Store(int64(10 * time.Minute))
+	_, err := refresher.ForceRefresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, refresher.TokenTTL())
+	assert.Equal(t, 5*time.Minute, refresher.EffectiveRefreshInterval())
+
+	// an unreported lifetime falls back to the configured TTL
+	expiresIn.Store(0)
+	_, err = refresher.ForceRefresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, refresher.TokenTTL())
+
+	t.Run("Run refreshes at half the reported lifetime", func(t *testing.T) {
+		expiresIn.Store(int64(20 * time.Millisecond))
+		atomic.StoreInt32(&calls, 0)
+		ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		refresher.Run(ctx)
+		// the default TTL of an hour would allow only the initial refresh
+		assert.GreaterOrEqual(t, atomic.LoadInt32(&calls), int32(5))
+	})
+}
+
+func TestRefresher_TokenWithExpiry(t *testing.T) {
+	refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+		return "foo", nil
+	}, time.Minute)
+	before := time.Now()
+	_, err := refresher.ForceRefresh(context.Background())
+	require.NoError(t, err)
+
+	tok, expiry, err := refresher.TokenWithExpiry(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "foo", tok)
+	assert.False(t, expiry.Before(before.Add(time.Minute)))
+	assert.False(t, expiry.After(time.Now().Add(time.Minute)))
+}
+
+func TestRefresher_WithOnRefresh(t *testing.T) {
+	shouldFail := true
+	var refresher *token.Refresher
+	var (
+		gotTokens []string
+		gotErrs   []error
+	)
+	refresher = token.NewRefresher(func(_ context.Context) (string, error) {
+		if shouldFail {
+			return "", werror.Error("failure")
+		}
+		return "foo", nil
+	}, time.Minute, token.WithOnRefresh(func(tok string, err error) {
+		// Token must not block while the hook runs
+		_, _ = refresher.Token(context.Background())
+		gotTokens = append(gotTokens, tok)
+		gotErrs = append(gotErrs, err)
+	}))
+
+	_, err := refresher.ForceRefresh(context.Background())
+	require.Error(t, err)
+	shouldFail = false
+	_, err = refresher.ForceRefresh(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "foo"}, gotTokens)
+	require.Len(t, gotErrs, 2)
+	assert.EqualError(t, gotErrs[0], "failure")
+	assert.NoError(t, gotErrs[1])
+}
+
+func TestRefresher_Done(t *testing.T) {
+	provideToken := func(_ context.Context) (string, error) {
+		return "foo", nil
+	}
+	waitForDone := func(t *testing.T, refresher *token.Refresher) {
+		select {
+		case <-refresher.Done():
+		case <-time.After(time.Second):
+			require.Fail(t, "Done was not closed after Run returned")
+		}
+	}
+
+	t.Run("context cancelled", func(t *testing.T) {
+		refresher := token.NewRefresher(provideToken, time.Hour)
+		ctx, cancel := context.WithCancel(context.Background())
+		go refresher.Run(ctx)
+		_, err := refresher.Token(ctx)
+		require.NoError(t, err)
+		cancel()
+		waitForDone(t, refresher)
+	})
+	t.Run("Stop", func(t *testing.T) {
+		refresher := token.NewRefresher(provideToken, time.Hour)
+		go refresher.Run(context.Background())
+		_, err := refresher.Token(context.Background())
+		require.NoError(t, err)
+		refresher.Stop()
+		refresher.Stop()
+		waitForDone(t, refresher)
+	})
+	t.Run("Stop before Run", func(t *testing.T) {
+		var calls int32
+		refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+			atomic.AddInt32(&calls, 1)
+			return "foo", nil
+		}, time.Hour)
+		refresher.Stop()
+		refresher.Run(context.Background())
+		waitForDone(t, refresher)
+		assert.Zero(t, atomic.LoadInt32(&calls), "provider must not be called")
+	})
+}
+
+func TestRefresher_Invalidate(t *testing.T) {
+	var calls int32
+	refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+		return "token-" + strconv.Itoa(int(atomic.AddInt32(&calls, 1))), nil
+	}, time.Hour)
+	events := refresher.Subscribe()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go refresher.Run(ctx)
+
+	<-events
+	tok, err := refresher.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", tok)
+
+	refresher.Invalidate()
+	select {
+	case event := <-events:
+		require.NoError(t, event.Err)
+	case <-ctx.Done():
+		require.Fail(t, "Run did not refresh the invalidated token")
+	}
+	tok, err = refresher.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", tok)
+}
+
+func TestRefresher_TokenErrorsAfterInvalidate(t *testing.T) {
+	refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+		return "foo", nil
+	}, time.Hour)
+	_, err := refresher.ForceRefresh(context.Background())
+	require.NoError(t, err)
+
+	refresher.Invalidate()
+	_, err = refresher.Token(context.Background())
+	require.EqualError(t, err, "all attempts to retrieve a token have failed: token was invalidated")
+
+	_, err = refresher.ForceRefresh(context.Background())
+	require.NoError(t, err)
+	tok, err := refresher.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "foo", tok)
+}
+
+func TestRefresher_WithMetrics(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	ctx := metrics.WithRegistry(context.Background(), registry)
+	var calls int32
+	refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return "", werror.Error("unavailable")
+		}
+		return "foo", nil
+	}, time.Hour, token.WithMetrics())
+
+	_, err := refresher.ForceRefresh(ctx)
+	require.Error(t, err)
+	_, err = refresher.ForceRefresh(ctx)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, registry.Timer(token.RefreshTimerName).Count())
+	assert.EqualValues(t, 1, registry.Meter(token.RefreshResultMeterName, metrics.MustNewTag("result", "success")).Count())
+	assert.EqualValues(t, 1, registry.Meter(token.RefreshResultMeterName, metrics.MustNewTag("result", "failure")).Count())
+}
+
+func TestRefresher_WithRefreshJitter(t *testing.T) {
+	// factors below 0 are treated as 0
+	for _, factor := range []float64{0, -0.1, math.NaN()} {
+		t.Run(fmt.Sprint(factor), func(t *testing.T) {
+			var calls int32
+			refreshed := make(chan struct{}, 10)
+			ttl := time.Hour
+			clock := newFakeClock()
+			refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+				atomic.AddInt32(&calls, 1)
+				return "foo", nil
+			}, ttl, token.WithClock(clock), token.WithRefreshJitter(factor), token.WithOnRefresh(func(string, error) {
+				refreshed <- struct{}{}
+			}))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go refresher.Run(ctx)
+
+			<-refreshed
+			clock.waitForTimer(t)
+			clock.Advance(ttl/2 - time.Nanosecond)
+			assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+			clock.Advance(time.Nanosecond)
+			<-refreshed
+			assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+		})
+	}
+	assert.NotPanics(t, func() { token.NewRefresher(nil, time.Hour, token.WithRefreshJitter(1.1)) })
+}
+
+func TestRefresher_OnDemandRefreshUsesCallerContext(t *testing.T) {
+	type ctxKey struct{}
+	gotValues := make(chan interface{}, 10)
+	refresher := token.NewRefresher(func(ctx context.Context) (string, error) {
+		gotValues <- ctx.Value(ctxKey{})
+		return "foo", nil
+	}, time.Hour, token.WithRefreshJitter(0))
+
+	runCtx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "run"))
+	defer cancel()
+	go refresher.Run(runCtx)
+	assert.Equal(t, "run", <-gotValues)
+
+	_, err := refresher.ForceRefresh(context.WithValue(context.Background(), ctxKey{}, "force"))
+	require.NoError(t, err)
+	assert.Equal(t, "force", <-gotValues)
+
+	_, err = refresher.TokenWithMinValidity(context.WithValue(context.Background(), ctxKey{}, "min-validity"), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "min-validity", <-gotValues)
+}
+
+// fakeClock is a token.Clock whose time only changes when it is advanced.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	created chan struct{}
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		created: make(chan struct{}, 100),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) token.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	c.created <- struct{}{}
+	return timer
+}
+
+// Advance moves the clock forward by d and fires the timers whose deadline has passed.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- c.now
+	}
+	c.timers = pending
+}
+
+// waitForTimer blocks until a timer has been created since the previous call.
+func (c *fakeClock) waitForTimer(t *testing.T) {
+	select {
+	case <-c.created:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for timer")
+	}
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// joinCountingContext is a context that increments a counter the first time its Done method is called. Callers that
+// join an in-flight token request wait on Done until the request completes, while the caller that makes the request
+// passes its context to the provider, so the counter reaches the number of joined callers before the request completes.
+type joinCountingContext struct {
+	context.Context
+	once   sync.Once
+	joined *int32
+}
+
+func newJoinCountingContext(joined *int32) context.Context {
+	return &joinCountingContext{Context: context.Background(), joined: joined}
+}
+
+func (c *joinCountingContext) Done() <-chan struct{} {
+	c.once.Do(func() {
+		atomic.AddInt32(c.joined, 1)
+	})
+	return c.Context.Done()
+}
+
+// waitForJoiners blocks until want callers have joined an in-flight request, as counted by joinCountingContext.
+func waitForJoiners(t *testing.T, joined *int32, want int32) {
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(joined) >= want
+	}, 5*time.Second, time.Millisecond, "callers did not join the in-flight request")
+}
@@ -16,30 +16,97 @@ package token
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"time"
 
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/go-oauth2-client/v2/oauth"
 	"github.com/palantir/pkg/retry"
 	werror "github.com/palantir/witchcraft-go-error"
 	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
 )
 
+// defaultRetriableOAuthErrors are the OAuth error codes defined by RFC 6749 that indicate a transient condition.
+var defaultRetriableOAuthErrors = []string{"server_error", "temporarily_unavailable"}
+
+// RetryingProviderOption configures optional behavior of a Provider returned by NewRetryingTokenProvider.
+type RetryingProviderOption func(*retryingProvider)
+
+// WithRetriableOAuthErrors sets the OAuth error codes, such as "temporarily_unavailable", for which a retrying provider
+// tries again. Errors that do not wrap an *oauth.OAuthError, such as network errors, are always retried, as are errors
+// for 429 and 5xx responses and responses with a Retry-After header, whatever their OAuth error code. Defaults to
+// "server_error" and "temporarily_unavailable".
+func WithRetriableOAuthErrors(codes ...string) RetryingProviderOption {
+	return func(p *retryingProvider) {
+		p.retriableOAuthErrors = make(map[string]struct{}, len(codes))
+		for _, code := range codes {
+			p.retriableOAuthErrors[code] = struct{}{}
+		}
+	}
+}
+
+type retryingProvider struct {
+	retriableOAuthErrors map[string]struct{}
+}
+
+// retriable returns whether an attempt that failed with err should be retried.
+func (p *retryingProvider) retriable(err error) bool {
+	var oauthErr *oauth.OAuthError
+	if !errors.As(err, &oauthErr) {
+		return true
+	}
+	// the status code takes precedence, since servers return RFC 6749 error bodies such as "slow_down" when rate
+	// limiting or overloaded
+	if statusCode, ok := httpclient.StatusCodeFromError(err); ok &&
+		(statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError) {
+		return true
+	}
+	if _, ok := oauth.RetryAfter(err); ok {
+		return true
+	}
+	_, ok := p.retriableOAuthErrors[oauthErr.ErrorType]
+	return ok
+}
+
 // NewRetryingTokenProvider takes a TokenProvider and uses it to create another TokenProvider that retries forever.
-func NewRetryingTokenProvider(provideToken Provider) Provider {
+// If the token endpoint responds with a Retry-After header, the next attempt is made no sooner than requested, up to a
+// delay of 5 minutes.
+// Attempts that fail with an OAuth error that is not retriable, such as "invalid_client", are not retried, since trying
+// again cannot succeed; see WithRetriableOAuthErrors.
+func NewRetryingTokenProvider(provideToken Provider, opts ...RetryingProviderOption) Provider {
+	p := &retryingProvider{}
+	WithRetriableOAuthErrors(defaultRetriableOAuthErrors...)(p)
+	for _, opt := range opts {
+		opt(p)
+	}
 	return func(ctx context.Context) (string, error) {
 		var numAttempts int
 		var token string
-		var err error
+		var err, terminalErr error
 		err = retry.Do(ctx, func() error {
 			token, err = provideToken(ctx)
 			if err == nil {
 				return nil
 			}
+			if !p.retriable(err) {
+				terminalErr = err
+				return nil
+			}
 			svc1log.FromContext(ctx).Error(
 				"failed to get new token; will try again",
 				svc1log.SafeParam("numAttempts", numAttempts),
 				svc1log.Stacktrace(err))
 			numAttempts++
+			waitRetryAfter(ctx, realClock{}, err)
 			return err
 		})
+		if terminalErr != nil {
+			return "", werror.Wrap(
+				terminalErr,
+				"token retrieval failed with a non-retriable error",
+				werror.SafeParam("numAttempts", numAttempts+1))
+		}
 		if err != nil {
 			return "", werror.Wrap(
 				err,
@@ -49,3 +116,26 @@ func NewRetryingTokenProvider(provideToken Provider) Provider {
 		return token, nil
 	}
 }
+
+// maxRetryAfter is the longest delay requested by a Retry-After header that is honored, so that a misconfigured or
+// hostile token endpoint cannot stall retries for hours.
+const maxRetryAfter = 5 * time.Minute
+
+// waitRetryAfter blocks until the delay requested by the token endpoint for the failed attempt that returned err, if
+// any and capped at maxRetryAfter, has elapsed on clock or ctx is done. The backoff of the caller is applied in
+// addition to this delay.
+func waitRetryAfter(ctx context.Context, clock Clock, err error) {
+	d, ok := oauth.RetryAfter(err)
+	if !ok || d <= 0 {
+		return
+	}
+	if d > maxRetryAfter {
+		d = maxRetryAfter
+	}
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C():
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/go-oauth2-client/v2/oauth"
+	"github.com/palantir/go-oauth2-client/v2/token"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRetryingTokenProvider(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	failing := func(errs ...error) (token.Provider, *int) {
+		var calls int
+		return func(_ context.Context) (string, error) {
+			calls++
+			if calls <= len(errs) {
+				return "", errs[calls-1]
+			}
+			return "token", nil
+		}, &calls
+	}
+	oauthErr := func(code string) error {
+		return werror.Wrap(&oauth.OAuthError{ErrorType: code}, "400 Bad Request")
+	}
+
+	t.Run("retries errors without an OAuth error code", func(t *testing.T) {
+		provider, calls := failing(werror.Error("503 Service Unavailable"))
+		tok, err := token.NewRetryingTokenProvider(provider)(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "token", tok)
+		assert.Equal(t, 2, *calls)
+	})
+	t.Run("retries retriable OAuth errors", func(t *testing.T) {
+		provider, calls := failing(oauthErr("temporarily_unavailable"))
+		_, err := token.NewRetryingTokenProvider(provider)(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+	t.Run("does not retry invalid_client", func(t *testing.T) {
+		provider, calls := failing(oauthErr("invalid_client"))
+		_, err := token.NewRetryingTokenProvider(provider)(ctx)
+		require.EqualError(t, err, "token retrieval failed with a non-retriable error: 400 Bad Request: invalid_client")
+		assert.Equal(t, 1, *calls)
+	})
+	t.Run("retries OAuth errors of 5xx responses", func(t *testing.T) {
+		provider, calls := failing(werror.Wrap(&oauth.OAuthError{ErrorType: "invalid_request"}, "503 Service Unavailable",
+			werror.SafeParam("statusCode", http.StatusServiceUnavailable)))
+		_, err := token.NewRetryingTokenProvider(provider)(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+	t.Run("overridden retriable codes", func(t *testing.T) {
+		provider, calls := failing(oauthErr("slow_down"))
+		_, err := token.NewRetryingTokenProvider(provider, token.WithRetriableOAuthErrors("slow_down"))(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+}
+
+func TestNewRetryingTokenProvider_RetryAfter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var calls int
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			rw.Header().Set("Retry-After", "1")
+			rw.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}), httpclient.WithMaxRetries(0))
+	require.NoError(t, err)
+	tokenClient := oauth.NewClientCredentialClient(tokenHTTPClient)
+
+	start := time.Now()
+	tok, err := token.NewRetryingTokenProvider(func(ctx context.Context) (string, error) {
+		return tokenClient.CreateClientCredentialToken(ctx, "id", "secret")
+	})(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token", tok)
+	assert.Equal(t, 2, calls)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestNewRetryingTokenProvider_RateLimitedOAuthError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var calls int
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			rw.Header().Set("Content-Type", "application/json")
+			rw.Header().Set("Retry-After", "1")
+			rw.WriteHeader(http.StatusTooManyRequests)
+			_, err := rw.Write([]byte(`{"error":"slow_down"}`))
+			assert.NoError(t, err)
+			return
+		}
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}), httpclient.WithMaxRetries(0))
+	require.NoError(t, err)
+	tokenClient := oauth.NewClientCredentialClient(tokenHTTPClient)
+
+	start := time.Now()
+	tok, err := token.NewRetryingTokenProvider(func(ctx context.Context) (string, error) {
+		return tokenClient.CreateClientCredentialToken(ctx, "id", "secret")
+	})(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token", tok)
+	assert.Equal(t, 2, calls)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestRefresher_CapsRetryAfter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var calls int
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			rw.Header().Set("Retry-After", "86400")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, err := rw.Write([]byte(`{"access_token":"token"}`))
+		assert.NoError(t, err)
+	}))
+	defer tokenSrv.Close()
+	tokenHTTPClient, err := httpclient.NewClient(httpclient.WithBaseURLs([]string{tokenSrv.URL}), httpclient.WithMaxRetries(0))
+	require.NoError(t, err)
+	tokenClient := oauth.NewClientCredentialClient(tokenHTTPClient)
+
+	clock := newFakeClock()
+	refresher := token.NewRefresher(func(ctx context.Context) (string, error) {
+		return tokenClient.CreateClientCredentialToken(ctx, "id", "secret")
+	}, time.Hour, token.WithClock(clock))
+	go refresher.Run(ctx)
+
+	// the day requested by the server is capped, so the retry is made once the clock has advanced by 5 minutes
+	clock.waitForTimer(t)
+	clock.Advance(5 * time.Minute)
+	require.Eventually(t, func() bool {
+		tok, err := refresher.Token(ctx)
+		return err == nil && tok == "token"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, calls)
+}
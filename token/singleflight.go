@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"context"
+	"sync"
+
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// NewSingleFlightProvider returns a Provider that coalesces concurrent calls into a single call to delegate: while a
+// call to delegate is in flight, other callers wait for it and receive its token or error instead of calling delegate
+// again. This prevents a burst of callers of a lazy Provider, such as one returned by NewCachingProvider wrapping an
+// expired token, from sending duplicate requests to the token endpoint. Results are not cached; a call made after the
+// in-flight call completes calls delegate again.
+//
+// The in-flight call uses the context of the caller that started it. Other callers stop waiting and return an error
+// once their own context is done.
+func NewSingleFlightProvider(delegate Provider) Provider {
+	var (
+		mu       sync.Mutex
+		inflight *inflightCall
+	)
+	return func(ctx context.Context) (string, error) {
+		mu.Lock()
+		if call := inflight; call != nil {
+			mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return "", werror.Wrap(ctx.Err(), "context completed while waiting for in-flight token request")
+			case <-call.done:
+				return call.token, call.err
+			}
+		}
+		call := &inflightCall{done: make(chan struct{})}
+		inflight = call
+		mu.Unlock()
+
+		call.token, call.err = delegate(ctx)
+
+		mu.Lock()
+		inflight = nil
+		mu.Unlock()
+		close(call.done)
+		return call.token, call.err
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/palantir/go-oauth2-client/v2/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSingleFlightProvider(t *testing.T) {
+	const numCallers = 100
+	var calls, joined int32
+	provider := token.NewSingleFlightProvider(func(_ context.Context) (string, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			waitForJoiners(t, &joined, numCallers-1)
+		}
+		return "token-" + strconv.Itoa(int(n)), nil
+	})
+
+	var done sync.WaitGroup
+	for i := 0; i < numCallers; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			tok, err := provider(newJoinCountingContext(&joined))
+			assert.NoError(t, err)
+			assert.Equal(t, "token-1", tok)
+		}()
+	}
+	done.Wait()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	tok, err := provider(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", tok)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"io"
+	"net/http"
+
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+type bearerTransport struct {
+	provideToken Provider
+	base         http.RoundTripper
+}
+
+// NewBearerTransport returns an http.RoundTripper that sets the Authorization header of each request to a bearer
+// token obtained from provideToken using the request's context, and then sends it using base. If base is nil,
+// http.DefaultTransport is used. The caller's request is not modified.
+func NewBearerTransport(provideToken Provider, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &bearerTransport{
+		provideToken: provideToken,
+		base:         base,
+	}
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.provideToken(req.Context())
+	if err != nil {
+		// RoundTrip must close the request body, even on errors
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, werror.Wrap(err, "failed to obtain token for request")
+	}
+	return t.base.RoundTrip(withBearerToken(req, token))
+}
+
+type refreshingBearerTransport struct {
+	refresher *Refresher
+	base      http.RoundTripper
+}
+
+// NewRefreshingBearerTransport returns an http.RoundTripper that behaves like one returned by NewBearerTransport for
+// refresher.Token, except that a request that receives a 401 Unauthorized response is retried exactly once with a new
+// token. This recovers from tokens that the server revoked before their TTL elapsed. The new token is obtained using
+// ForceRefresh, unless another request has already replaced the rejected token, so concurrent rejected requests do not
+// each refresh the token. Requests whose body cannot be replayed, because the body is set and GetBody is nil, are not
+// retried.
+func NewRefreshingBearerTransport(refresher *Refresher, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &refreshingBearerTransport{
+		refresher: refresher,
+		base:      base,
+	}
+}
+
+func (t *refreshingBearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	token, err := t.refresher.Token(ctx)
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, werror.Wrap(err, "failed to obtain token for request")
+	}
+	resp, err := t.base.RoundTrip(withBearerToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	newToken, err := t.refresher.Token(ctx)
+	if err != nil || newToken == token {
+		newToken, err = t.refresher.ForceRefresh(ctx)
+	}
+	if err != nil {
+		// return the original response rather than an error, since the request itself was sent successfully
+		return resp, nil
+	}
+	retryReq := withBearerToken(req, newToken)
+	if req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retryReq.Body = body
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return t.base.RoundTrip(retryReq)
+}
+
+// withBearerToken returns a shallow copy of req with its Authorization header set to a bearer token.
+func withBearerToken(req *http.Request, token string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/go-oauth2-client/v2/token"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBearerTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: token.NewBearerTransport(func(_ context.Context) (string, error) {
+		return "token", nil
+	}, nil)}
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, req.Header.Get("Authorization"), "caller's request must not be modified")
+
+	client = &http.Client{Transport: token.NewBearerTransport(func(_ context.Context) (string, error) {
+		return "", werror.Error("failure")
+	}, nil)}
+	_, err = client.Get(srv.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to obtain token for request: failure")
+}
+
+func TestNewRefreshingBearerTransport(t *testing.T) {
+	var (
+		issued int32
+		valid  atomic.Value
+	)
+	valid.Store("")
+	refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+		tok := "token-" + strconv.Itoa(int(atomic.AddInt32(&issued, 1)))
+		valid.Store(tok)
+		return tok, nil
+	}, time.Hour)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "payload", string(body))
+		if req.Header.Get("Authorization") != "Bearer "+valid.Load().(string) {
+			rw.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: token.NewRefreshingBearerTransport(refresher, nil)}
+	post := func() int {
+		resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	_, err := refresher.ForceRefresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, post())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&issued))
+
+	// the server revokes the current token, so the request is retried with a new one
+	valid.Store("revoked")
+	assert.Equal(t, http.StatusOK, post())
+	assert.EqualValues(t, 2, atomic.LoadInt32(&issued))
+
+	assert.Equal(t, http.StatusOK, post())
+	assert.EqualValues(t, 2, atomic.LoadInt32(&issued))
+}
+
+func TestNewRefreshingBearerTransport_RetriesOnce(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		rw.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	refresher := token.NewRefresher(func(_ context.Context) (string, error) {
+		return "token", nil
+	}, time.Hour)
+	_, err := refresher.ForceRefresh(context.Background())
+	require.NoError(t, err)
+	client := &http.Client{Transport: token.NewRefreshingBearerTransport(refresher, nil)}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&hits))
+}